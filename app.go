@@ -2,14 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"notifyme/internal/api"
+	"notifyme/internal/auth"
 	"notifyme/internal/config"
+	"notifyme/internal/deeplink"
+	"notifyme/internal/eventbus"
 	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	_ "notifyme/internal/notifier/desktop" // 注册桌面通知渠道
 	"notifyme/internal/scheduler"
+	"notifyme/internal/singleinstance"
+	"notifyme/internal/store"
 	"notifyme/internal/tray"
 	"notifyme/pkg/types"
 
@@ -21,11 +32,23 @@ type App struct {
 	ctx           context.Context
 	ctxMu         sync.RWMutex
 	config        *types.Config
+	configMu      sync.Mutex // 保护 config，保存配置期间一直持有，避免并发保存时互相覆盖
 	scheduler     *scheduler.Scheduler
+	api           *api.Server  // 本机 HTTP 接口，初始化失败时为 nil
 	shouldQuit    bool         // 标志是否应该退出程序
 	quitMu        sync.RWMutex // 保护 shouldQuit 的互斥锁
 	showingWindow int32        // 原子标志，表示是否正在显示窗口（0=否，1=是）
 	windowVisible int32        // 原子标志，表示窗口是否可见（0=隐藏，1=显示）
+
+	loginMu     sync.Mutex
+	githubLogin *githubLogin // 正在进行的 GitHub 设备授权登录，没有时为 nil
+
+	startErr error // 初始化调度器失败的原因，不为 nil 时启动后提示用户并退出
+}
+
+// githubLogin 一次 GitHub 设备授权登录
+type githubLogin struct {
+	cancel context.CancelFunc
 }
 
 // NewApp creates a new App application struct
@@ -37,6 +60,8 @@ func NewApp() *App {
 		cfg = &types.Config{
 			PollInterval: 60,
 			LogLevel:     "debug",
+			Sinks:        config.DefaultSinks(),
+			Digest:       config.DefaultDigest(),
 		}
 	}
 
@@ -55,7 +80,12 @@ func NewApp() *App {
 	}
 
 	// 初始化调度器
-	sched := scheduler.NewScheduler(cfg)
+	// 通知数据库被守护进程（notifymectl daemon）占用等情况下无法继续运行，等界面启动后提示用户再退出
+	sched, err := scheduler.NewScheduler(cfg)
+	if err != nil {
+		logger.Errorf("初始化调度器失败: %v", err)
+		return &App{config: cfg, startErr: err}
+	}
 
 	app := &App{
 		config:    cfg,
@@ -63,6 +93,14 @@ func NewApp() *App {
 	}
 
 	// 初始化系统托盘
+	tray.SetPauseHandlers(
+		func(hours int) {
+			app.PauseNotifications(hours)
+		},
+		func() {
+			app.ResumeNotifications()
+		},
+	)
 	tray.Init(
 		func() {
 			// 打开 UI 的回调
@@ -74,6 +112,26 @@ func NewApp() *App {
 		},
 	)
 
+	// 把调度器事件转发给前端，数据源健康状态变化时更新托盘提示
+	eventbus.Subscribe(app.handleEvent)
+	// 点击汇总通知时打开主界面
+	sched.SetOpenAppHandler(app.ShowWindow)
+
+	// 初始化本机接口（按配置决定是否监听），失败不影响主程序
+	if server, err := api.New(sched, scheduler.DataDir()); err != nil {
+		logger.Errorf("初始化本机接口失败: %v", err)
+	} else {
+		app.api = server
+		if err := server.Reconfigure(cfg.API); err != nil {
+			logger.Errorf("启动本机接口失败: %v", err)
+		}
+	}
+
+	// 注册 notifyme:// 链接处理程序，系统通知上的按钮通过链接把动作交给本程序
+	if err := deeplink.Register(); err != nil {
+		logger.Warnf("注册链接处理程序失败: %v", err)
+	}
+
 	// 启动调度器
 	if err := sched.Start(); err != nil {
 		logger.Errorf("启动调度器失败: %v", err)
@@ -91,15 +149,81 @@ func (a *App) startup(ctx context.Context) {
 	// 应用启动时，窗口默认是可见的
 	atomic.StoreInt32(&a.windowVisible, 1)
 	logger.Info("应用启动完成")
+
+	if a.startErr != nil {
+		go a.showStartError(ctx)
+		return
+	}
+
+	// 处理启动参数，之后由其他实例转发的参数也交给 handleArgs
+	if args := os.Args[1:]; len(args) > 0 {
+		go a.handleArgs(args)
+	}
+	singleinstance.SetHandler(a.handleArgs)
+}
+
+// showStartError 提示初始化失败的原因，用户关闭对话框后退出程序
+func (a *App) showStartError(ctx context.Context) {
+	message := fmt.Sprintf("NotifyMe 启动失败: %v", a.startErr)
+	if errors.Is(a.startErr, store.ErrLocked) {
+		message = "通知数据库正被其他 NotifyMe 进程占用（如 notifymectl daemon 守护进程），请先停止该进程后再启动桌面版。"
+	}
+	if _, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+		Type:    runtime.ErrorDialog,
+		Title:   "启动失败",
+		Message: message,
+	}); err != nil {
+		logger.Errorf("显示错误对话框失败: %v", err)
+	}
+	a.Quit()
+}
+
+// handleArgs 处理启动参数或其他实例转发的参数
+// --show 显示主界面，--check-now 立即检查所有数据源，notifyme:// 链接执行链接中的动作；没有参数（重复启动）时显示主界面
+func (a *App) handleArgs(args []string) {
+	if len(args) == 0 {
+		a.ShowWindow()
+		return
+	}
+	for _, arg := range args {
+		switch {
+		case arg == "--show":
+			a.ShowWindow()
+		case arg == "--check-now":
+			a.TriggerCheck()
+		case strings.HasPrefix(strings.ToLower(arg), deeplink.Scheme+"://"):
+			a.handleLink(arg)
+		default:
+			logger.Warnf("未知的参数: %s", arg)
+		}
+	}
 }
 
 // GetConfig 获取配置
 func (a *App) GetConfig() *types.Config {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
 	return a.config
 }
 
 // SaveConfig 保存配置
 func (a *App) SaveConfig(cfg *types.Config) error {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+	return a.saveConfigLocked(cfg)
+}
+
+// updateConfig 在当前配置的副本上修改部分字段后保存，修改期间其他保存操作需要等待
+func (a *App) updateConfig(fn func(cfg *types.Config)) error {
+	a.configMu.Lock()
+	defer a.configMu.Unlock()
+	cfg := *a.config
+	fn(&cfg)
+	return a.saveConfigLocked(&cfg)
+}
+
+// saveConfigLocked 保存配置并应用到调度器和本机接口（调用方需持有 a.configMu）
+func (a *App) saveConfigLocked(cfg *types.Config) error {
 	if err := config.Save(cfg); err != nil {
 		return err
 	}
@@ -117,22 +241,353 @@ func (a *App) SaveConfig(cfg *types.Config) error {
 
 	a.config = cfg
 	a.scheduler.UpdateConfig(cfg)
+	if a.api != nil {
+		if err := a.api.Reconfigure(cfg.API); err != nil {
+			logger.Errorf("启动本机接口失败: %v", err)
+			return err
+		}
+	}
 	return nil
 }
 
+// GetAPIInfo 获取本机接口地址和访问令牌，未启用时地址为空
+func (a *App) GetAPIInfo() map[string]string {
+	if a.api == nil {
+		return map[string]string{"url": "", "token": ""}
+	}
+	return map[string]string{
+		"url":   a.api.URL(),
+		"token": a.api.Token(),
+	}
+}
+
+// StartGitHubLogin 开始 GitHub 设备授权登录，返回需要展示给用户的用户码和验证地址，并在浏览器中打开验证地址
+// clientID 为空时使用配置中的 Client ID；之后在后台轮询，用户完成授权后 token 自动保存到配置，
+// 结果通过 github:login 事件通知前端。再次调用会取消之前未完成的登录
+func (a *App) StartGitHubLogin(clientID string) (*auth.DeviceCode, error) {
+	if clientID == "" {
+		clientID = a.GetConfig().GitHub.ClientID
+	}
+	a.CancelGitHubLogin()
+
+	githubAuth := auth.NewGitHubDeviceAuth(clientID)
+	ctx, cancel := context.WithCancel(context.Background())
+	code, err := githubAuth.RequestDeviceCode(ctx)
+	if err != nil {
+		cancel()
+		logger.Errorf("GitHub 登录失败: %v", err)
+		return nil, err
+	}
+
+	login := &githubLogin{cancel: cancel}
+	a.loginMu.Lock()
+	a.githubLogin = login
+	a.loginMu.Unlock()
+
+	a.ctxMu.RLock()
+	appCtx := a.ctx
+	a.ctxMu.RUnlock()
+	if appCtx != nil {
+		runtime.BrowserOpenURL(appCtx, code.VerificationURI)
+	}
+
+	go a.waitGitHubLogin(ctx, login, githubAuth, code, clientID)
+	return code, nil
+}
+
+// CancelGitHubLogin 取消正在进行的 GitHub 设备授权登录
+func (a *App) CancelGitHubLogin() {
+	a.loginMu.Lock()
+	login := a.githubLogin
+	a.githubLogin = nil
+	a.loginMu.Unlock()
+
+	if login != nil {
+		login.cancel()
+		logger.Info("已取消 GitHub 登录")
+	}
+}
+
+// waitGitHubLogin 等待用户完成授权，成功后把 token 和 Client ID 保存到配置
+func (a *App) waitGitHubLogin(ctx context.Context, login *githubLogin, githubAuth *auth.GitHubAuth, code *auth.DeviceCode, clientID string) {
+	defer login.cancel()
+
+	token, err := githubAuth.PollDeviceToken(ctx, code)
+
+	a.loginMu.Lock()
+	current := a.githubLogin == login
+	if current {
+		a.githubLogin = nil
+	}
+	a.loginMu.Unlock()
+	if !current {
+		// 已取消或被新的登录取代
+		return
+	}
+
+	if err == nil {
+		// 等待授权期间界面可能保存过配置，只在当前配置上修改 GitHub 登录信息
+		err = a.updateConfig(func(cfg *types.Config) {
+			cfg.GitHub = types.GitHubAuth{Token: token.AccessToken, ClientID: clientID}
+		})
+	}
+	if err != nil {
+		logger.Errorf("GitHub 登录失败: %v", err)
+		eventbus.Publish(eventbus.GitHubLogin, eventbus.LoginEvent{Error: err.Error()})
+		return
+	}
+	logger.Info("GitHub 登录成功，token 已保存到配置")
+	eventbus.Publish(eventbus.GitHubLogin, eventbus.LoginEvent{})
+}
+
+// GetRules 获取通知规则
+func (a *App) GetRules() []types.Rule {
+	return a.GetConfig().Rules
+}
+
+// SaveRules 保存通知规则，立即对之后拉取的通知生效
+func (a *App) SaveRules(rules []types.Rule) error {
+	return a.updateConfig(func(cfg *types.Config) {
+		cfg.Rules = rules
+	})
+}
+
 // GetStatus 获取应用状态
 func (a *App) GetStatus() map[string]interface{} {
 	return map[string]interface{}{
 		"running":       a.scheduler.IsRunning(),
-		"poll_interval": a.config.PollInterval,
+		"poll_interval": a.GetConfig().PollInterval,
+		"sources":       a.scheduler.SourceStatus(),
+		"sinks":         a.scheduler.SinkStatus(),
+		"dnd":           a.scheduler.DNDStatus(),
 	}
 }
 
+// safeLinkActions 不需要确认即可执行的链接动作
+// 任何网页或程序都可以打开 notifyme:// 链接，其他会修改通知状态的动作需要先显示主界面并由用户确认；
+// 本程序生成的链接（如 Windows 通知上的按钮）带有本进程的令牌，不需要确认
+var safeLinkActions = map[string]bool{
+	deeplink.ActionOpen:  true,
+	deeplink.ActionReply: true,
+}
+
+// linkActionLabels 确认对话框中链接动作的说明
+var linkActionLabels = map[string]string{
+	deeplink.ActionRead:       "标记为已读",
+	deeplink.ActionSnooze:     "稍后提醒",
+	deeplink.ActionMuteThread: "静音所在的会话",
+	deeplink.ActionDone:       "标记为完成",
+}
+
+// handleLink 执行 notifyme:// 链接中的动作
+func (a *App) handleLink(raw string) {
+	link, err := deeplink.Parse(raw)
+	if err != nil {
+		logger.Warnf("忽略链接: %v", err)
+		return
+	}
+	logger.Infof("处理链接: %s", raw)
+
+	if !safeLinkActions[link.Action] && !link.Trusted() {
+		a.ShowWindow()
+		if !a.confirmLink(link) {
+			logger.Infof("已取消链接动作: %s", raw)
+			return
+		}
+	}
+
+	if link.Action == deeplink.ActionOpen && link.ID == "" {
+		a.ShowWindow()
+	} else {
+		err = a.executeAction(link.ID, link.NotificationAction(), link.Duration)
+	}
+	if err != nil {
+		logger.Errorf("执行链接 %s 失败: %v", raw, err)
+	}
+}
+
+// confirmLink 弹出对话框让用户确认链接中的动作，无法显示对话框时视为取消
+func (a *App) confirmLink(link *deeplink.Link) bool {
+	a.ctxMu.RLock()
+	ctx := a.ctx
+	a.ctxMu.RUnlock()
+	if ctx == nil {
+		return false
+	}
+
+	title := link.ID
+	if notification, err := a.scheduler.GetNotification(link.ID); err == nil {
+		title = notification.Title
+	}
+	message := fmt.Sprintf("外部链接请求把通知「%s」%s，是否继续？", title, linkActionLabels[link.Action])
+	if link.Action == deeplink.ActionSnooze {
+		message = fmt.Sprintf("外部链接请求在 %s 后重新提醒通知「%s」，是否继续？", link.Duration, title)
+	}
+
+	result, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+		Type:          runtime.QuestionDialog,
+		Title:         "确认操作",
+		Message:       message,
+		DefaultButton: "No",
+	})
+	if err != nil {
+		logger.Errorf("显示确认对话框失败: %v", err)
+		return false
+	}
+	return result == "Yes"
+}
+
+// ExecuteAction 执行通知上的动作（打开、标记已读、完成、静音会话、稍后提醒、回复）
+// snoozeMinutes 为稍后提醒的分钟数，只用于稍后提醒，为 0 时使用默认时长；
+// 需要打开链接的动作在浏览器中打开，通知没有链接时打开主界面
+func (a *App) ExecuteAction(notificationID, actionID string, snoozeMinutes int) error {
+	return a.executeAction(notificationID, actionID, time.Duration(snoozeMinutes)*time.Minute)
+}
+
+// executeAction 执行通知上的动作，snooze 为稍后提醒的时长
+func (a *App) executeAction(notificationID, actionID string, snooze time.Duration) error {
+	url, err := a.scheduler.ExecuteAction(notificationID, actionID, snooze)
+	if err != nil {
+		return err
+	}
+	if actionID != types.ActionOpen && url == "" {
+		return nil
+	}
+
+	a.ctxMu.RLock()
+	ctx := a.ctx
+	a.ctxMu.RUnlock()
+	if url == "" || url == notifier.AppLink || ctx == nil {
+		a.ShowWindow()
+	} else {
+		runtime.BrowserOpenURL(ctx, url)
+	}
+	return nil
+}
+
+// handleEvent 把事件总线上的事件以同名 Wails 事件转发给前端
+func (a *App) handleEvent(event eventbus.Event) {
+	if event.Name == eventbus.HealthChanged {
+		a.updateTrayTooltip()
+	}
+
+	a.ctxMu.RLock()
+	ctx := a.ctx
+	a.ctxMu.RUnlock()
+	if ctx != nil {
+		runtime.EventsEmit(ctx, event.Name, event.Data)
+	}
+}
+
+// updateTrayTooltip 在托盘提示中显示各数据源的健康状态
+func (a *App) updateTrayTooltip() {
+	tooltip := fmt.Sprintf("NotifyMe - 消息通知 (PID: %d)", os.Getpid())
+	if summary := scheduler.HealthSummary(a.scheduler.SourceStatus()); summary != "" {
+		tooltip += "\n" + summary
+	}
+	tray.SetTooltip(tooltip)
+}
+
 // GetRecentNotifications 获取最近的通知列表
 func (a *App) GetRecentNotifications() []*types.Notification {
 	return a.scheduler.GetRecentNotifications()
 }
 
+// QueryNotifications 按条件查询通知历史（不限于最近 50 条）
+func (a *App) QueryNotifications(query types.NotificationQuery) ([]*types.Notification, error) {
+	return a.scheduler.QueryNotifications(query)
+}
+
+// GetRecentThreads 获取最近有更新的会话，同一 GitHub 线程或 ld246 帖子的多次更新合并为一组
+func (a *App) GetRecentThreads() []*types.NotificationThread {
+	return a.scheduler.GetRecentThreads()
+}
+
+// QueryThreads 按条件查询会话
+func (a *App) QueryThreads(query types.NotificationQuery) ([]*types.NotificationThread, error) {
+	return a.scheduler.QueryThreads(query)
+}
+
+// GetThread 获取会话中所有更新的时间线
+func (a *App) GetThread(key string) ([]*types.Notification, error) {
+	return a.scheduler.GetThread(key)
+}
+
+// MarkThreadRead 将会话中的所有更新标记为已读
+func (a *App) MarkThreadRead(key string) (int, error) {
+	return a.scheduler.MarkThreadRead(key)
+}
+
+// MarkRead 将通知标记为已读
+func (a *App) MarkRead(id string) error {
+	return a.scheduler.MarkRead(id)
+}
+
+// MarkUnread 将通知标记为未读
+func (a *App) MarkUnread(id string) error {
+	return a.scheduler.MarkUnread(id)
+}
+
+// MarkAllRead 将指定来源的通知全部标记为已读，source 为空表示全部来源
+func (a *App) MarkAllRead(source string) (int, error) {
+	return a.scheduler.MarkAllRead(source)
+}
+
+// MarkRepoRead 将 GitHub 仓库（owner/repo）内的通知全部标记为已读
+func (a *App) MarkRepoRead(repo string) (int, error) {
+	return a.scheduler.MarkRepoRead(repo)
+}
+
+// MarkLd246CategoryRead 将 ld246 指定分类（commented, at, reply, comment2ed, following）的消息全部标记为已读
+func (a *App) MarkLd246CategoryRead(category string) (int, error) {
+	return a.scheduler.MarkLd246CategoryRead(category)
+}
+
+// Archive 归档通知（GitHub 通知会同时在站点上标记为完成）
+func (a *App) Archive(id string) error {
+	return a.scheduler.Archive(id)
+}
+
+// Unarchive 取消归档
+func (a *App) Unarchive(id string) error {
+	return a.scheduler.Unarchive(id)
+}
+
+// Star 设置或取消星标
+func (a *App) Star(id string, starred bool) error {
+	return a.scheduler.Star(id, starred)
+}
+
+// Snooze 稍后提醒：通知从收件箱中隐藏，minutes 分钟后重新提醒
+func (a *App) Snooze(id string, minutes int) error {
+	return a.scheduler.Snooze(id, time.Now().Add(time.Duration(minutes)*time.Minute))
+}
+
+// SnoozeUntil 稍后提醒：通知从收件箱中隐藏，到指定时间（Unix 毫秒）重新提醒
+func (a *App) SnoozeUntil(id string, until int64) error {
+	return a.scheduler.Snooze(id, time.UnixMilli(until))
+}
+
+// CancelSnooze 取消稍后提醒
+func (a *App) CancelSnooze(id string) error {
+	return a.scheduler.CancelSnooze(id)
+}
+
+// UnmuteThread 取消静音通知所在的会话，之后的更新恢复提醒
+func (a *App) UnmuteThread(id string) error {
+	return a.scheduler.UnmuteThread(id)
+}
+
+// PauseNotifications 暂停弹出通知指定小时数，期间的通知会在恢复后汇总提醒
+func (a *App) PauseNotifications(hours int) {
+	a.scheduler.PauseNotifications(time.Duration(hours) * time.Hour)
+}
+
+// ResumeNotifications 恢复弹出通知
+func (a *App) ResumeNotifications() {
+	a.scheduler.ResumeNotifications()
+}
+
 // TriggerCheck 手动触发检查
 func (a *App) TriggerCheck() {
 	a.scheduler.TriggerCheck()
@@ -312,11 +767,18 @@ func (a *App) Quit() {
 	a.shouldQuit = true
 	a.quitMu.Unlock()
 
-	// 在 goroutine 中停止调度器，避免阻塞退出流程
+	a.CancelGitHubLogin()
+
+	// 在 goroutine 中停止本机接口和调度器，避免阻塞退出流程
 	stopDone := make(chan struct{})
 	go func() {
 		defer close(stopDone)
-		a.scheduler.Stop()
+		if a.api != nil {
+			a.api.Close()
+		}
+		if a.scheduler != nil {
+			a.scheduler.Stop()
+		}
 	}()
 
 	// 等待调度器停止，但设置超时
@@ -335,6 +797,10 @@ func (a *App) Quit() {
 	if ctx != nil {
 		// 先退出 Wails 应用
 		runtime.Quit(ctx)
+		if a.startErr != nil {
+			// 初始化失败时没有创建托盘
+			return
+		}
 		// 等待一小段时间，让 Wails 应用有时间退出
 		// 然后退出托盘
 		go func() {
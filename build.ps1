@@ -84,7 +84,7 @@ if ($Clean) {
 
 Write-Info "[2/6] Syncing icon file..."
 if (Test-Path "build\windows\icon.ico") {
-    Copy-Item -Path "build\windows\icon.ico" -Destination "internal\tray\icon.ico" -Force
+    Copy-Item -Path "build\windows\icon.ico" -Destination "internal\assets\icon.ico" -Force
     Write-Success "[OK] Icon file synced"
 } else {
     Write-Info "[WARN] build\windows\icon.ico not found, skipping icon sync"
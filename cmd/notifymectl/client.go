@@ -0,0 +1,192 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"notifyme/internal/api"
+	"notifyme/internal/config"
+	"notifyme/internal/scheduler"
+	"notifyme/pkg/types"
+)
+
+// apiTimeout 访问本机接口的超时时间
+const apiTimeout = 30 * time.Second
+
+// notificationService 通知历史的查询和标记操作
+// 由调度器（直接打开通知数据库）或本机接口客户端（数据库被正在运行的实例占用时）提供，使用完后需要调用 Stop
+type notificationService interface {
+	QueryNotifications(query types.NotificationQuery) ([]*types.Notification, error)
+	QueryThreads(query types.NotificationQuery) ([]*types.NotificationThread, error)
+	MarkRead(id string) error
+	MarkAllRead(source string) (int, error)
+	MarkRepoRead(repo string) (int, error)
+	MarkThreadRead(key string) (int, error)
+	UnmuteThread(id string) error
+	Stop()
+}
+
+// apiClient 正在运行的实例（桌面版或守护进程）的本机接口客户端
+// 通知数据库同一时间只能被一个进程打开，数据库被占用时 check、list、mark-read 和 unmute-thread 通过本机接口完成
+type apiClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// newAPIClient 按配置创建本机接口客户端，访问令牌从数据目录读取
+func newAPIClient(cfg types.APIConfig) (*apiClient, error) {
+	if !cfg.Enabled {
+		return nil, fmt.Errorf("本机接口未启用（api.enabled），无法通过正在运行的实例完成操作")
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = config.DefaultAPIPort
+	}
+	token, err := api.ReadToken(scheduler.DataDir())
+	if err != nil {
+		return nil, err
+	}
+	return &apiClient{
+		baseURL: fmt.Sprintf("http://127.0.0.1:%d", port),
+		token:   token,
+		client:  &http.Client{Timeout: apiTimeout},
+	}, nil
+}
+
+// do 发送请求，成功时把 JSON 响应解析到 out（为 nil 时忽略响应内容）
+func (c *apiClient) do(method, path string, params url.Values, out interface{}) error {
+	target := c.baseURL + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("访问本机接口失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var body struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(data, &body) == nil && body.Error != "" {
+			return fmt.Errorf("本机接口返回错误: %s", body.Error)
+		}
+		return fmt.Errorf("本机接口返回错误: %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("解析本机接口响应失败: %w", err)
+	}
+	return nil
+}
+
+// QueryNotifications 查询通知
+func (c *apiClient) QueryNotifications(query types.NotificationQuery) ([]*types.Notification, error) {
+	var result []*types.Notification
+	err := c.do(http.MethodGet, "/api/v1/notifications", queryParams(query), &result)
+	return result, err
+}
+
+// QueryThreads 按会话查询通知
+func (c *apiClient) QueryThreads(query types.NotificationQuery) ([]*types.NotificationThread, error) {
+	var result []*types.NotificationThread
+	err := c.do(http.MethodGet, "/api/v1/threads", queryParams(query), &result)
+	return result, err
+}
+
+// MarkRead 把一条通知标记为已读
+func (c *apiClient) MarkRead(id string) error {
+	return c.do(http.MethodPost, "/api/v1/notifications/"+url.PathEscape(id)+"/read", nil, nil)
+}
+
+// MarkAllRead 把指定来源（为空表示全部来源）的通知全部标记为已读，返回标记的条数
+func (c *apiClient) MarkAllRead(source string) (int, error) {
+	params := url.Values{}
+	if source != "" {
+		params.Set("source", source)
+	}
+	return c.markAllRead(params)
+}
+
+// MarkRepoRead 把 GitHub 仓库内的通知全部标记为已读，返回标记的条数
+func (c *apiClient) MarkRepoRead(repo string) (int, error) {
+	return c.markAllRead(url.Values{"repo": {repo}})
+}
+
+// markAllRead 调用全部标记已读接口，返回标记的条数
+func (c *apiClient) markAllRead(params url.Values) (int, error) {
+	var result struct {
+		Count int `json:"count"`
+	}
+	err := c.do(http.MethodPost, "/api/v1/notifications/read-all", params, &result)
+	return result.Count, err
+}
+
+// MarkThreadRead 把会话标记为已读，返回标记的条数
+func (c *apiClient) MarkThreadRead(key string) (int, error) {
+	var result struct {
+		Count int `json:"count"`
+	}
+	err := c.do(http.MethodPost, "/api/v1/threads/"+url.PathEscape(key)+"/read", nil, &result)
+	return result.Count, err
+}
+
+// UnmuteThread 取消静音通知所在的会话
+func (c *apiClient) UnmuteThread(id string) error {
+	return c.do(http.MethodPost, "/api/v1/notifications/"+url.PathEscape(id)+"/unmute-thread", nil, nil)
+}
+
+// TriggerCheck 让正在运行的实例立即检查所有数据源（后台进行）
+func (c *apiClient) TriggerCheck() error {
+	return c.do(http.MethodPost, "/api/v1/check", nil, nil)
+}
+
+// Stop 客户端没有需要释放的资源，与调度器保持一致
+func (c *apiClient) Stop() {}
+
+// queryParams 把查询条件转换为本机接口的查询参数
+func queryParams(query types.NotificationQuery) url.Values {
+	params := url.Values{}
+	if query.Source != "" {
+		params.Set("source", query.Source)
+	}
+	bools := map[string]*bool{
+		"read":     query.Read,
+		"archived": query.Archived,
+		"starred":  query.Starred,
+		"snoozed":  query.Snoozed,
+	}
+	for name, value := range bools {
+		if value != nil {
+			params.Set(name, strconv.FormatBool(*value))
+		}
+	}
+	ints := map[string]int64{
+		"since":  query.Since,
+		"until":  query.Until,
+		"limit":  int64(query.Limit),
+		"offset": int64(query.Offset),
+	}
+	for name, value := range ints {
+		if value > 0 {
+			params.Set(name, strconv.FormatInt(value, 10))
+		}
+	}
+	return params
+}
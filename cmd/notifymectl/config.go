@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"notifyme/internal/config"
+	"notifyme/pkg/types"
+)
+
+// runConfig 查看、修改和检查配置
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: notifymectl config <get|set|validate|path> [参数]")
+	}
+
+	switch args[0] {
+	case "get":
+		return runConfigGet(args[1:])
+	case "set":
+		return runConfigSet(args[1:])
+	case "validate":
+		return runConfigValidate(args[1:])
+	case "path":
+		fmt.Println(config.Path())
+		return nil
+	default:
+		return fmt.Errorf("未知的 config 子命令: %s", args[0])
+	}
+}
+
+// runConfigGet 输出整个配置或指定键（点号分隔，如 sources.github.poll_interval、sinks.0.url）的值
+func runConfigGet(args []string) error {
+	fs := newFlagSet("config get", "[键]")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出（对象和数组始终以 JSON 格式输出）")
+	fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	tree, err := configTree(cfg)
+	if err != nil {
+		return err
+	}
+
+	value := interface{}(tree)
+	if key := fs.Arg(0); key != "" {
+		if value, err = lookup(tree, splitKey(key)); err != nil {
+			return err
+		}
+	}
+
+	// 字符串和数字直接输出，便于在脚本中使用
+	switch v := value.(type) {
+	case string:
+		if !*asJSON {
+			fmt.Println(v)
+			return nil
+		}
+	case float64, bool:
+		if !*asJSON {
+			fmt.Println(v)
+			return nil
+		}
+	}
+	return printJSON(value)
+}
+
+// runConfigSet 修改配置中指定键的值并保存，值按 JSON 解析，解析失败时作为字符串
+func runConfigSet(args []string) error {
+	fs := newFlagSet("config set", "<键> <值>")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("需要指定键和值")
+	}
+	key, raw := fs.Arg(0), fs.Arg(1)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	tree, err := configTree(cfg)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+	if err := assign(tree, splitKey(key), value); err != nil {
+		return err
+	}
+
+	updated, err := configFromTree(tree)
+	if err != nil {
+		return err
+	}
+	if err := config.Save(updated); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "已保存 %s\n", key)
+	return nil
+}
+
+// runConfigValidate 检查配置文件是否有效
+func runConfigValidate(args []string) error {
+	fs := newFlagSet("config validate", "")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	path := config.Path()
+	var err error
+	if _, statErr := os.Stat(path); statErr != nil {
+		err = fmt.Errorf("配置文件不存在: %s", path)
+	} else {
+		_, err = loadConfig()
+	}
+
+	if *asJSON {
+		result := map[string]interface{}{"path": path, "valid": err == nil}
+		if err != nil {
+			result["error"] = err.Error()
+		}
+		if printErr := printJSON(result); printErr != nil {
+			return printErr
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+	fmt.Printf("配置有效: %s\n", path)
+	return nil
+}
+
+// configTree 把配置转换为 JSON 对象树
+func configTree(cfg *types.Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %w", err)
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("解析配置失败: %w", err)
+	}
+	return tree, nil
+}
+
+// configFromTree 把 JSON 对象树转换回配置，不认识的键视为错误
+func configFromTree(tree map[string]interface{}) (*types.Config, error) {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, fmt.Errorf("序列化配置失败: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var cfg types.Config
+	if err := decoder.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("无效的配置: %w", err)
+	}
+	return &cfg, nil
+}
+
+// splitKey 拆分点号分隔的键
+func splitKey(key string) []string {
+	return strings.Split(key, ".")
+}
+
+// lookup 按路径获取对象树中的值，数组使用下标
+func lookup(node interface{}, path []string) (interface{}, error) {
+	for i, part := range path {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			child, ok := v[part]
+			if !ok {
+				return nil, fmt.Errorf("配置项不存在: %s", strings.Join(path[:i+1], "."))
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("配置项不存在: %s", strings.Join(path[:i+1], "."))
+			}
+			node = v[index]
+		default:
+			return nil, fmt.Errorf("配置项不存在: %s", strings.Join(path[:i+1], "."))
+		}
+	}
+	return node, nil
+}
+
+// assign 按路径设置对象树中的值，对象中不存在的中间节点会自动创建
+func assign(tree map[string]interface{}, path []string, value interface{}) error {
+	var node interface{} = tree
+	for i, part := range path {
+		last := i == len(path)-1
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if last {
+				v[part] = value
+				return nil
+			}
+			child, ok := v[part]
+			if !ok || child == nil {
+				child = make(map[string]interface{})
+				v[part] = child
+			}
+			node = child
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return fmt.Errorf("配置项不存在: %s", strings.Join(path[:i+1], "."))
+			}
+			if last {
+				v[index] = value
+				return nil
+			}
+			node = v[index]
+		default:
+			return fmt.Errorf("配置项 %s 不是对象或数组", strings.Join(path[:i], "."))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"notifyme/internal/api"
+	"notifyme/internal/logger"
+	"notifyme/internal/scheduler"
+)
+
+// runDaemon 以无界面模式运行调度器，直到收到退出信号
+func runDaemon(args []string) error {
+	fs := newFlagSet("daemon", "")
+	fs.Parse(args)
+
+	cfg, err := loadHeadlessConfig()
+	if err != nil {
+		return err
+	}
+	sched, err := scheduler.NewScheduler(cfg)
+	if err != nil {
+		return fmt.Errorf("初始化调度器失败: %w", err)
+	}
+
+	// 本机接口创建失败不影响轮询
+	server, err := api.New(sched, scheduler.DataDir())
+	if err != nil {
+		logger.Errorf("初始化本机接口失败: %v", err)
+	} else {
+		if err := server.Reconfigure(cfg.API); err != nil {
+			logger.Errorf("启动本机接口失败: %v", err)
+		}
+	}
+
+	if err := sched.Start(); err != nil {
+		return fmt.Errorf("启动调度器失败: %w", err)
+	}
+	logger.Infof("守护进程已启动 (PID: %d)", os.Getpid())
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig == syscall.SIGHUP {
+			logger.Info("收到 SIGHUP，重新加载配置")
+			cfg, err := loadHeadlessConfig()
+			if err != nil {
+				logger.Errorf("重新加载配置失败，继续使用原配置: %v", err)
+				continue
+			}
+			sched.UpdateConfig(cfg)
+			if server != nil {
+				if err := server.Reconfigure(cfg.API); err != nil {
+					logger.Errorf("启动本机接口失败: %v", err)
+				}
+			}
+			continue
+		}
+
+		logger.Infof("收到信号: %v，开始退出", sig)
+		break
+	}
+
+	signal.Stop(sigChan)
+	if server != nil {
+		server.Close()
+	}
+	sched.Stop()
+	logger.Info("守护进程已退出")
+	return nil
+}
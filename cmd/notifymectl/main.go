@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"notifyme/internal/config"
+	"notifyme/internal/logger"
+	"notifyme/internal/scheduler"
+	"notifyme/internal/store"
+	"notifyme/pkg/types"
+)
+
+// 无界面的命令行入口，适合在服务器或虚拟机上运行轮询，把通知转发到 Webhook、文件等渠道，
+// 也可以在脚本中检查、查询和标记通知（-json 输出便于解析）
+// 不导入 Wails、托盘和桌面通知相关的包，配置中的 desktop 渠道会被忽略
+
+// command 子命令
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"daemon", "以无界面模式持续轮询，收到 SIGINT/SIGTERM 时退出，收到 SIGHUP 时重新加载配置", runDaemon},
+	{"check", "立即检查数据源一次，投递并保存新通知，输出检查结果", runCheck},
+	{"list", "查询通知历史", runList},
+	{"mark-read", "把通知标记为已读并同步到站点", runMarkRead},
+	{"unmute-thread", "取消静音通知所在的会话", runUnmuteThread},
+	{"config", "查看、修改和检查配置（get、set、validate、path）", runConfig},
+	{"sources", "列出数据源或检查数据源连接（list、test）", runSources},
+}
+
+// verbose 是否把日志输出到标准错误（daemon 始终输出到标准输出）
+var verbose bool
+
+func main() {
+	flag.Usage = usage
+	configPath := flag.String("config", "", "配置文件路径（默认按桌面版规则查找 config.json）")
+	flag.BoolVar(&verbose, "v", false, "把日志输出到标准错误")
+	flag.Parse()
+
+	if *configPath != "" {
+		config.SetPath(*configPath)
+	}
+
+	name := flag.Arg(0)
+	if name == "" || name == "help" {
+		usage()
+		return
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if cmd.name != "daemon" {
+			if verbose {
+				logger.SetConsole(os.Stderr)
+			} else {
+				logger.SetConsole(io.Discard)
+			}
+		}
+		if err := cmd.run(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "未知的命令: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+// usage 输出帮助信息
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: notifymectl [-config 配置文件] [-v] <命令> [参数]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "使用 notifymectl <命令> -h 查看命令的参数")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "全局参数:")
+	flag.PrintDefaults()
+}
+
+// newFlagSet 创建子命令的参数解析器
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "用法: notifymectl %s [参数] %s\n\n参数:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// loadConfig 加载配置并初始化日志
+func loadConfig() (*types.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %w", err)
+	}
+
+	logLevel := cfg.LogLevel
+	if logLevel == "" {
+		logLevel = "debug"
+	}
+	if err := logger.Init(logLevel, true); err != nil {
+		return nil, fmt.Errorf("初始化日志失败: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadHeadlessConfig 加载配置，并去掉无界面模式下不可用的桌面通知渠道
+func loadHeadlessConfig() (*types.Config, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	sinks := make([]types.SinkConfig, 0, len(cfg.Sinks))
+	for _, sink := range cfg.Sinks {
+		if sink.Type == "desktop" {
+			if sink.Enabled {
+				logger.Infof("无界面模式不支持桌面通知，忽略通知渠道 %s", sink.Name)
+			}
+			continue
+		}
+		sinks = append(sinks, sink)
+	}
+	cfg.Sinks = sinks
+	if len(sinks) == 0 {
+		logger.Warn("没有可用的通知渠道，新通知只会保存到通知历史")
+	}
+	return cfg, nil
+}
+
+// openScheduler 加载配置并创建调度器（不启动轮询），使用完后需要调用 Stop
+// 通知数据库被正在运行的桌面版或守护进程占用时，改为返回该实例的本机接口客户端
+func openScheduler() (notificationService, error) {
+	cfg, err := loadHeadlessConfig()
+	if err != nil {
+		return nil, err
+	}
+	sched, err := scheduler.NewScheduler(cfg)
+	if errors.Is(err, store.ErrLocked) {
+		client, clientErr := newAPIClient(cfg.API)
+		if clientErr != nil {
+			return nil, fmt.Errorf("%w\n%v", err, clientErr)
+		}
+		logger.Infof("通知数据库被占用，通过本机接口 %s 访问正在运行的实例", client.baseURL)
+		return client, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("初始化调度器失败: %w", err)
+	}
+	return sched, nil
+}
+
+// printJSON 以缩进格式把结果输出到标准输出
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// splitList 解析逗号分隔的列表，忽略空项
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
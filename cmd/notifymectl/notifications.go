@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"notifyme/internal/scheduler"
+	"notifyme/pkg/types"
+)
+
+// checkTimeout 单次检查所有数据源的超时时间
+const checkTimeout = 2 * time.Minute
+
+// runCheck 立即检查数据源一次
+func runCheck(args []string) error {
+	fs := newFlagSet("check", "")
+	sources := fs.String("source", "", "只检查这些数据源（逗号分隔），默认检查所有启用的数据源")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	service, err := openScheduler()
+	if err != nil {
+		return err
+	}
+	defer service.Stop()
+
+	sched, ok := service.(*scheduler.Scheduler)
+	if !ok {
+		return triggerCheck(service.(*apiClient), *sources, *asJSON)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	results := sched.CheckSources(ctx, splitList(*sources))
+
+	if *asJSON {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range results {
+			if result.Error != "" {
+				fmt.Printf("%s: 检查失败: %s\n", result.Source, result.Error)
+				continue
+			}
+			fmt.Printf("%s: %d 条新通知\n", result.Source, len(result.Notifications))
+			for _, notification := range result.Notifications {
+				fmt.Printf("  %s  %s\n", formatTime(notification), notification.Title)
+			}
+		}
+	}
+
+	for _, result := range results {
+		if result.Error != "" {
+			return fmt.Errorf("部分数据源检查失败")
+		}
+	}
+	return nil
+}
+
+// triggerCheck 通知数据库被占用时，让正在运行的实例检查所有数据源，新通知由该实例投递
+func triggerCheck(client *apiClient, sources string, asJSON bool) error {
+	if sources != "" {
+		return fmt.Errorf("通知数据库被正在运行的实例占用，只能让该实例检查所有数据源，不支持 -source")
+	}
+	if err := client.TriggerCheck(); err != nil {
+		return err
+	}
+	if asJSON {
+		return printJSON(map[string]bool{"triggered": true})
+	}
+	fmt.Println("已让正在运行的实例检查所有数据源，新通知由该实例投递")
+	return nil
+}
+
+// runList 查询通知历史
+func runList(args []string) error {
+	fs := newFlagSet("list", "")
+	source := fs.String("source", "", "只显示该来源的通知")
+	unread := fs.Bool("unread", false, "只显示未读通知")
+	starred := fs.Bool("starred", false, "只显示加星标的通知")
+	archived := fs.Bool("archived", false, "只显示已归档的通知")
+	all := fs.Bool("all", false, "包含已归档和稍后提醒的通知（默认只显示收件箱）")
+	since := fs.String("since", "", "只显示该时间之后的通知，如 24h、2025-01-15")
+	limit := fs.Int("limit", 50, "最多显示条数，0 表示不限")
+	offset := fs.Int("offset", 0, "跳过条数")
+	threads := fs.Bool("threads", false, "按会话合并显示")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	query := types.NotificationQuery{Source: *source, Limit: *limit, Offset: *offset}
+	yes, no := true, false
+	if *unread {
+		query.Read = &no
+	}
+	if *starred {
+		query.Starred = &yes
+	}
+	switch {
+	case *archived:
+		query.Archived = &yes
+	case !*all:
+		query.Archived = &no
+		query.Snoozed = &no
+	}
+	if *since != "" {
+		t, err := parseSince(*since)
+		if err != nil {
+			return err
+		}
+		query.Since = t.UnixMilli()
+	}
+
+	sched, err := openScheduler()
+	if err != nil {
+		return err
+	}
+	defer sched.Stop()
+
+	if *threads {
+		result, err := sched.QueryThreads(query)
+		if err != nil {
+			return fmt.Errorf("查询会话失败: %w", err)
+		}
+		if *asJSON {
+			return printJSON(result)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "时间\t来源\t更新\t未读\t会话\t标题")
+		for _, thread := range result {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n",
+				formatTime(thread.Latest), thread.Latest.Source, thread.Count, thread.Unread, thread.Key, thread.Latest.Title)
+		}
+		return w.Flush()
+	}
+
+	result, err := sched.QueryNotifications(query)
+	if err != nil {
+		return fmt.Errorf("查询通知失败: %w", err)
+	}
+	if *asJSON {
+		return printJSON(result)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "时间\t来源\t状态\tID\t标题")
+	for _, notification := range result {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			formatTime(notification), notification.Source, stateLabel(notification), notification.ID, notification.Title)
+	}
+	return w.Flush()
+}
+
+// runMarkRead 把通知标记为已读
+func runMarkRead(args []string) error {
+	fs := newFlagSet("mark-read", "[通知 ID...]")
+	all := fs.Bool("all", false, "标记全部通知（可用 -source 限定来源）")
+	source := fs.String("source", "", "与 -all 一起使用，只标记该来源的通知")
+	repo := fs.String("repo", "", "标记 GitHub 仓库（owner/repo）内的全部通知")
+	thread := fs.String("thread", "", "标记会话中的全部更新")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	ids := fs.Args()
+	if !*all && *repo == "" && *thread == "" && len(ids) == 0 {
+		fs.Usage()
+		return fmt.Errorf("需要指定通知 ID，或使用 -all、-repo、-thread")
+	}
+
+	sched, err := openScheduler()
+	if err != nil {
+		return err
+	}
+	// Stop 会等待已读状态同步到站点
+	defer sched.Stop()
+
+	marked := 0
+	switch {
+	case *all:
+		marked, err = sched.MarkAllRead(*source)
+	case *repo != "":
+		marked, err = sched.MarkRepoRead(*repo)
+	case *thread != "":
+		marked, err = sched.MarkThreadRead(*thread)
+	default:
+		for _, id := range ids {
+			if err = sched.MarkRead(id); err != nil {
+				break
+			}
+			marked++
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("标记已读失败: %w", err)
+	}
+
+	if *asJSON {
+		return printJSON(map[string]int{"marked": marked})
+	}
+	fmt.Printf("已将 %d 条通知标记为已读\n", marked)
+	return nil
+}
+
+// runUnmuteThread 取消静音通知所在的会话
+func runUnmuteThread(args []string) error {
+	fs := newFlagSet("unmute-thread", "<通知 ID...>")
+	fs.Parse(args)
+
+	ids := fs.Args()
+	if len(ids) == 0 {
+		fs.Usage()
+		return fmt.Errorf("需要指定通知 ID")
+	}
+
+	sched, err := openScheduler()
+	if err != nil {
+		return err
+	}
+	defer sched.Stop()
+
+	for _, id := range ids {
+		if err := sched.UnmuteThread(id); err != nil {
+			return fmt.Errorf("取消静音会话失败: %w", err)
+		}
+		fmt.Printf("已取消静音通知 %s 所在的会话\n", id)
+	}
+	return nil
+}
+
+// parseSince 解析时间参数：时长（如 24h，表示多久之前）、日期（2006-01-02）或 RFC 3339 时间
+func parseSince(value string) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无效的时间: %s（支持 24h、2025-01-15 或 RFC 3339 格式）", value)
+}
+
+// formatTime 格式化通知时间
+func formatTime(notification *types.Notification) string {
+	return time.UnixMilli(notification.UnixMilli()).Format("2006-01-02 15:04")
+}
+
+// stateLabel 通知状态说明
+func stateLabel(notification *types.Notification) string {
+	label := "已读"
+	if !notification.Read {
+		label = "未读"
+	}
+	if notification.Archived {
+		label += ",已归档"
+	}
+	if notification.Starred {
+		label += ",星标"
+	}
+	return label
+}
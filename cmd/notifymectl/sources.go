@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"notifyme/internal/monitor"
+)
+
+// testTimeout 检查单个数据源连接的超时时间
+const testTimeout = 30 * time.Second
+
+// sourceInfo 数据源信息
+type sourceInfo struct {
+	Name         string   `json:"name"`
+	Enabled      bool     `json:"enabled"`
+	PollInterval int      `json:"poll_interval"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// testResult 数据源连接检查结果
+type testResult struct {
+	Source  string `json:"source"`
+	OK      bool   `json:"ok"`
+	Latency int64  `json:"latency_ms"`      // 请求耗时（毫秒）
+	Error   string `json:"error,omitempty"` // 检查失败的原因
+}
+
+// runSources 列出数据源或检查数据源连接
+// 不打开通知数据库，桌面版或守护进程运行时也可以使用
+func runSources(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("用法: notifymectl sources <list|test> [参数]")
+	}
+
+	switch args[0] {
+	case "list":
+		return runSourcesList(args[1:])
+	case "test":
+		return runSourcesTest(args[1:])
+	default:
+		return fmt.Errorf("未知的 sources 子命令: %s", args[0])
+	}
+}
+
+// runSourcesList 列出所有已注册的数据源
+func runSourcesList(args []string) error {
+	fs := newFlagSet("sources list", "")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	enabled := make(map[string]monitor.Source)
+	for _, src := range monitor.NewSources(cfg) {
+		enabled[src.Name()] = src
+	}
+
+	result := make([]sourceInfo, 0)
+	for _, name := range monitor.Registered() {
+		info := sourceInfo{
+			Name:         name,
+			Enabled:      cfg.SourceEnabled(name),
+			PollInterval: cfg.SourcePollInterval(name),
+			Capabilities: []string{},
+		}
+		if src, ok := enabled[name]; ok {
+			for _, capability := range src.Capabilities() {
+				info.Capabilities = append(info.Capabilities, string(capability))
+			}
+		}
+		result = append(result, info)
+	}
+
+	if *asJSON {
+		return printJSON(result)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "名称\t启用\t轮询间隔\t能力")
+	for _, info := range result {
+		fmt.Fprintf(w, "%s\t%v\t%ds\t%s\n", info.Name, info.Enabled, info.PollInterval, strings.Join(info.Capabilities, ","))
+	}
+	return w.Flush()
+}
+
+// runSourcesTest 检查数据源的连接和凭据，不拉取通知
+func runSourcesTest(args []string) error {
+	fs := newFlagSet("sources test", "[数据源...]")
+	asJSON := fs.Bool("json", false, "以 JSON 格式输出")
+	fs.Parse(args)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	sources := make(map[string]monitor.Source)
+	for _, src := range monitor.NewSources(cfg) {
+		sources[src.Name()] = src
+	}
+	names := fs.Args()
+	if len(names) == 0 {
+		for _, name := range monitor.Registered() {
+			if _, ok := sources[name]; ok {
+				names = append(names, name)
+			}
+		}
+	}
+
+	results := make([]testResult, 0, len(names))
+	failed := false
+	for _, name := range names {
+		result := testResult{Source: name}
+		start := time.Now()
+		err := testSource(sources[name], name)
+		result.Latency = time.Since(start).Milliseconds()
+		if err != nil {
+			result.Error = err.Error()
+			failed = true
+		} else {
+			result.OK = true
+		}
+		results = append(results, result)
+	}
+
+	if *asJSON {
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	} else {
+		for _, result := range results {
+			if result.OK {
+				fmt.Printf("%s: 正常（%d ms）\n", result.Source, result.Latency)
+			} else {
+				fmt.Printf("%s: 失败: %s\n", result.Source, result.Error)
+			}
+		}
+	}
+
+	if failed {
+		return fmt.Errorf("部分数据源检查失败")
+	}
+	return nil
+}
+
+// testSource 检查一个数据源的连接
+func testSource(src monitor.Source, name string) error {
+	if src == nil {
+		return fmt.Errorf("数据源 %s 不存在或未启用", name)
+	}
+	tester, ok := src.(monitor.Tester)
+	if !ok {
+		return fmt.Errorf("数据源 %s 不支持连接检查", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	return tester.Test(ctx)
+}
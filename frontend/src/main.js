@@ -79,6 +79,10 @@ function initApp() {
                 if (forceUpdate || document.activeElement !== logLevelSelect) {
                     logLevelSelect.value = config.log_level || 'debug';
                 }
+                const githubClientIdInput = document.getElementById('github-client-id');
+                if (forceUpdate || document.activeElement !== githubClientIdInput) {
+                    githubClientIdInput.value = (config.github && config.github.client_id) || '';
+                }
                 
                 // 对于密码输入框，需要更谨慎处理：
                 // 1. 如果强制更新（初始化时），无条件更新
@@ -283,45 +287,90 @@ function initApp() {
     // 加载通知列表
     async function loadNotifications() {
         try {
-            const notifications = await app.GetRecentNotifications();
+            // 同一会话的多次更新合并为一条，显示更新次数
+            const threads = await app.GetRecentThreads();
             const listEl = document.getElementById('notifications-list');
             
             if (!listEl) return;
             
-            if (!notifications || notifications.length === 0) {
+            if (!threads || threads.length === 0) {
                 listEl.innerHTML = '<div class="notification-empty">暂无通知</div>';
                 return;
             }
             
-            listEl.innerHTML = notifications.map(notif => {
-                const timeStr = formatTime(notif.time);
+            listEl.innerHTML = threads.map(thread => {
+                const notif = thread.latest;
+                const countStr = thread.count > 1 ? ` · ${thread.count} 次更新` : '';
+                const authorStr = notif.author ? ` · ${notif.author}` : '';
+                const mutedStr = thread.muted ? ' · 已静音' : '';
+                const timeStr = formatTime(notif.time) + authorStr + countStr + mutedStr;
                 const sourceStr = notif.source === 'github' ? 'GitHub' : 'ld246';
                 const title = notif.title || notif.content || '无标题';
                 const link = notif.link || '#';
+                // 点击通知本身即为打开，其余动作显示为按钮；已静音的会话显示取消静音
+                const actions = (notif.actions || [])
+                    .filter(action => action.id !== 'open' && !(thread.muted && action.id === 'mute-thread'))
+                    .map(action => `<button class="notification-action" data-action="${action.id}">${action.label}</button>`)
+                    .join('') + (thread.muted ? '<button class="notification-action" data-action="unmute-thread">取消静音</button>' : '');
                 
                 return `
-                    <div class="notification-item" data-link="${link}" data-time="${notif.time}">
+                    <div class="notification-item" data-id="${notif.id}" data-link="${link}" data-time="${notif.time}">
                         <div class="notification-header">
                             <div class="notification-title" title="${title}">${title}</div>
                             <div class="notification-source">${sourceStr}</div>
                         </div>
                         <div class="notification-time">${timeStr}</div>
+                        ${actions ? `<div class="notification-actions">${actions}</div>` : ''}
                     </div>
                 `;
             }).join('');
             
-            // 绑定点击事件
+            // 绑定点击事件：打开通知并标记为已读，后端不可用时直接打开链接
             listEl.querySelectorAll('.notification-item').forEach(item => {
-                item.addEventListener('click', () => {
-                    const link = item.getAttribute('data-link');
-                    if (link && link !== '#') {
-                        if (typeof window.runtime !== 'undefined' && typeof window.runtime.BrowserOpenURL === 'function') {
-                            window.runtime.BrowserOpenURL(link);
-                        } else {
-                            window.open(link, '_blank');
+                const id = item.getAttribute('data-id');
+                item.addEventListener('click', async () => {
+                    try {
+                        await app.ExecuteAction(id, 'open', 0);
+                    } catch (error) {
+                        console.error('打开通知失败:', error);
+                        const link = item.getAttribute('data-link');
+                        if (link && link !== '#') {
+                            if (typeof window.runtime !== 'undefined' && typeof window.runtime.BrowserOpenURL === 'function') {
+                                window.runtime.BrowserOpenURL(link);
+                            } else {
+                                window.open(link, '_blank');
+                            }
                         }
                     }
                 });
+                item.querySelectorAll('.notification-action').forEach(button => {
+                    button.addEventListener('click', async (event) => {
+                        event.stopPropagation();
+                        try {
+                            const action = button.getAttribute('data-action');
+                            if (action === 'unmute-thread') {
+                                await app.UnmuteThread(id);
+                                await loadNotifications();
+                            } else if (action === 'snooze') {
+                                const minutes = prompt('多少分钟后重新提醒？', '60');
+                                if (minutes === null) {
+                                    return;
+                                }
+                                const parsed = parseInt(minutes, 10);
+                                if (!(parsed > 0)) {
+                                    alert('请输入大于 0 的分钟数');
+                                    return;
+                                }
+                                await app.ExecuteAction(id, action, parsed);
+                            } else {
+                                await app.ExecuteAction(id, action, 0);
+                            }
+                        } catch (error) {
+                            console.error('执行通知动作失败:', error);
+                            alert('操作失败: ' + error);
+                        }
+                    });
+                });
             });
         } catch (error) {
             console.error('加载通知列表失败:', error);
@@ -331,13 +380,19 @@ function initApp() {
     // 保存配置
     async function saveConfig() {
         try {
+            // 在当前配置基础上修改，保留界面上没有的配置项（通知渠道、数据源等）
+            const current = await app.GetConfig() || {};
             const config = {
+                ...current,
                 poll_interval: parseInt(document.getElementById('poll-interval-input').value) || 60,
                 log_level: document.getElementById('log-level-select').value || 'debug',
                 github: {
-                    token: document.getElementById('github-token').value || ''
+                    ...current.github,
+                    token: document.getElementById('github-token').value || '',
+                    client_id: document.getElementById('github-client-id').value.trim()
                 },
                 ld246: {
+                    ...current.ld246,
                     token: document.getElementById('ld246-token').value || ''
                 }
             };
@@ -392,15 +447,10 @@ function initApp() {
             // 触发后端检查
             await app.TriggerCheck();
             
-            // 重置倒计时
+            // 重置倒计时（新通知由 notifications:new 事件刷新）
             if (isRunning) {
                 startCountdown();
             }
-            
-            // 等待一小段时间后刷新通知列表（给后端时间处理）
-            setTimeout(async () => {
-                await loadNotifications();
-            }, 1000);
         } catch (error) {
             console.error('触发检查失败:', error);
         } finally {
@@ -422,16 +472,74 @@ function initApp() {
         markFieldAsModified('ld246-token');
     });
 
+    // GitHub 设备授权登录：显示用户码，授权结果通过 github:login 事件返回
+    const githubLoginBtn = document.getElementById('github-login-btn');
+    const githubLoginStatus = document.getElementById('github-login-status');
+    githubLoginBtn.addEventListener('click', async () => {
+        githubLoginBtn.disabled = true;
+        githubLoginStatus.textContent = '正在申请用户码...';
+        try {
+            const clientId = document.getElementById('github-client-id').value.trim();
+            const code = await app.StartGitHubLogin(clientId);
+            githubLoginStatus.textContent = `请在 ${code.verification_uri} 输入用户码 ${code.user_code}，等待授权中...`;
+        } catch (error) {
+            githubLoginStatus.textContent = '登录失败: ' + error;
+            githubLoginBtn.disabled = false;
+        }
+    });
+
     // 初始化（强制更新所有字段）
     loadConfig(true);
     loadStatus();
     loadNotifications();
 
-    // 定期刷新状态和通知列表（每2秒刷新一次，确保及时显示新通知）
-    setInterval(() => {
-        loadStatus();
-        loadNotifications();
-    }, 2000);
+    // 订阅后端事件，收到事件时立即刷新，不再定时轮询
+    if (typeof window.runtime !== 'undefined' && typeof window.runtime.EventsOn === 'function') {
+        const on = window.runtime.EventsOn;
+
+        // 新通知或通知状态变化（包括在托盘、系统通知或本机接口中操作）
+        on('notifications:new', () => loadNotifications());
+        on('notifications:changed', () => loadNotifications());
+
+        // 检查开始时显示检查中，结束后重置倒计时
+        on('check:started', (event) => {
+            const runningStatusEl = document.getElementById('running-status');
+            if (runningStatusEl && isRunning) {
+                runningStatusEl.textContent = '检查 ' + event.source + ' 中...';
+            }
+        });
+        on('check:finished', () => {
+            if (isRunning) {
+                startCountdown();
+            }
+            loadStatus();
+        });
+
+        on('source:health', () => loadStatus());
+        on('github:login', async (event) => {
+            githubLoginBtn.disabled = false;
+            if (event.error) {
+                githubLoginStatus.textContent = '登录失败: ' + event.error;
+                return;
+            }
+            // token 已由后端保存，只更新 Token 输入框，不覆盖其他未保存的修改
+            const config = await app.GetConfig();
+            githubTokenInput.value = (config && config.github && config.github.token) || '';
+            clearFieldModified('github-token');
+            githubLoginStatus.textContent = '登录成功，Token 已保存';
+        });
+        on('config:reloaded', () => {
+            loadConfig();
+            loadStatus();
+        });
+    } else {
+        // 运行时不支持事件时退回定时刷新
+        console.warn('EventsOn 不可用，改为定时刷新');
+        setInterval(() => {
+            loadStatus();
+            loadNotifications();
+        }, 2000);
+    }
 
     // 定期更新时间显示（每秒更新一次，让相对时间更准确）
     setInterval(() => {
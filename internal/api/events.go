@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/pkg/types"
+)
+
+const (
+	heartbeatInterval = 30 * time.Second // 事件流心跳间隔，避免连接被代理或客户端判定为超时
+	subscriberBuffer  = 16               // 每个订阅者最多缓存的未发送批次
+)
+
+// broker 把新通知分发给所有事件流订阅者
+// 订阅者处理太慢、缓冲区已满时丢弃这一批通知，不阻塞调度器
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[chan []*types.Notification]struct{}
+}
+
+// newBroker 创建事件分发器
+func newBroker() *broker {
+	return &broker{subscribers: make(map[chan []*types.Notification]struct{})}
+}
+
+// subscribe 订阅新通知
+func (b *broker) subscribe() chan []*types.Notification {
+	ch := make(chan []*types.Notification, subscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+// unsubscribe 取消订阅
+func (b *broker) unsubscribe(ch chan []*types.Notification) {
+	b.mu.Lock()
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+}
+
+// publish 把新通知发送给所有订阅者
+func (b *broker) publish(notifications []*types.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- notifications:
+		default:
+			logger.Warnf("事件流订阅者处理太慢，丢弃 %d 条通知", len(notifications))
+		}
+	}
+}
+
+// handleEvents 以 Server-Sent Events 推送新通知，每条通知是一个 notification 事件
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "不支持事件流")
+		return
+	}
+
+	ch := s.events.subscribe()
+	defer s.events.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	flusher.Flush()
+	logger.Debugf("事件流已连接: %s", r.RemoteAddr)
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			logger.Debugf("事件流已断开: %s", r.RemoteAddr)
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case notifications := <-ch:
+			for _, notification := range notifications {
+				data, err := json.Marshal(notification)
+				if err != nil {
+					logger.Warnf("序列化通知失败: %v", err)
+					continue
+				}
+				if _, err := fmt.Fprintf(w, "event: notification\nid: %s\ndata: %s\n\n", notification.ID, data); err != nil {
+					return
+				}
+			}
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"notifyme/internal/deeplink"
+	"notifyme/internal/logger"
+	"notifyme/internal/scheduler"
+	"notifyme/internal/store"
+	"notifyme/pkg/types"
+)
+
+// eventsPath 事件流地址
+const eventsPath = "/api/v1/events"
+
+// routes 注册所有接口
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/status", s.handleStatus)
+	mux.HandleFunc("GET /api/v1/notifications", s.handleNotifications)
+	mux.HandleFunc("POST /api/v1/notifications/read-all", s.handleMarkAllRead)
+	mux.HandleFunc("POST /api/v1/notifications/{id}/read", s.handleMarkRead)
+	mux.HandleFunc("POST /api/v1/notifications/{id}/actions/{action}", s.handleAction)
+	mux.HandleFunc("POST /api/v1/notifications/{id}/unmute-thread", s.handleUnmuteThread)
+	mux.HandleFunc("GET /api/v1/threads", s.handleThreads)
+	mux.HandleFunc("GET /api/v1/threads/{key}", s.handleThread)
+	mux.HandleFunc("POST /api/v1/threads/{key}/read", s.handleMarkThreadRead)
+	mux.HandleFunc("POST /api/v1/check", s.handleCheck)
+	mux.HandleFunc("GET "+eventsPath, s.handleEvents)
+	return s.authorize(mux)
+}
+
+// handleStatus 调度器、数据源、通知渠道和勿扰状态，以及未读通知条数
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	read, archived := false, false
+	unread, err := s.sched.CountNotifications(types.NotificationQuery{Read: &read, Archived: &archived})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"running": s.sched.IsRunning(),
+		"unread":  unread,
+		"sources": s.sched.SourceStatus(),
+		"sinks":   s.sched.SinkStatus(),
+		"dnd":     s.sched.DNDStatus(),
+	})
+}
+
+// handleNotifications 按条件查询通知历史
+func (s *Server) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	notifications, err := s.sched.QueryNotifications(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, notifications)
+}
+
+// handleThreads 按条件查询会话，limit 和 offset 按会话计数
+func (s *Server) handleThreads(w http.ResponseWriter, r *http.Request) {
+	query, err := parseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	threads, err := s.sched.QueryThreads(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, threads)
+}
+
+// handleThread 会话中所有更新的时间线
+func (s *Server) handleThread(w http.ResponseWriter, r *http.Request) {
+	updates, err := s.sched.GetThread(r.PathValue("key"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(updates) == 0 {
+		writeError(w, http.StatusNotFound, "会话不存在")
+		return
+	}
+	writeJSON(w, http.StatusOK, updates)
+}
+
+// handleMarkRead 把一条通知标记为已读
+func (s *Server) handleMarkRead(w http.ResponseWriter, r *http.Request) {
+	if err := s.sched.MarkRead(r.PathValue("id")); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleUnmuteThread 取消静音通知所在的会话
+func (s *Server) handleUnmuteThread(w http.ResponseWriter, r *http.Request) {
+	if err := s.sched.UnmuteThread(r.PathValue("id")); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAction 执行通知上的动作，返回需要打开的链接（由调用方决定是否打开）
+// 稍后提醒可以用参数 d 指定时长（如 30m、2h、1d），格式与应用链接相同
+func (s *Server) handleAction(w http.ResponseWriter, r *http.Request) {
+	var snooze time.Duration
+	if value := r.URL.Query().Get("d"); value != "" {
+		d, err := deeplink.ParseDuration(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		snooze = d
+	}
+
+	link, err := s.sched.ExecuteAction(r.PathValue("id"), r.PathValue("action"), snooze)
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			writeError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, scheduler.ErrUnsupportedAction):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"url": link})
+}
+
+// handleMarkAllRead 把指定来源（?source=，为空表示全部来源）或 GitHub 仓库（?repo=owner/repo）的通知全部标记为已读
+func (s *Server) handleMarkAllRead(w http.ResponseWriter, r *http.Request) {
+	var count int
+	var err error
+	if repo := r.URL.Query().Get("repo"); repo != "" {
+		count, err = s.sched.MarkRepoRead(repo)
+	} else {
+		count, err = s.sched.MarkAllRead(r.URL.Query().Get("source"))
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"count": count})
+}
+
+// handleMarkThreadRead 把会话中的所有更新标记为已读
+func (s *Server) handleMarkThreadRead(w http.ResponseWriter, r *http.Request) {
+	count, err := s.sched.MarkThreadRead(r.PathValue("key"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"count": count})
+}
+
+// handleCheck 立即检查所有数据源，检查在后台进行，新通知通过事件流推送
+func (s *Server) handleCheck(w http.ResponseWriter, r *http.Request) {
+	if !s.sched.IsRunning() {
+		writeError(w, http.StatusServiceUnavailable, "调度器未运行")
+		return
+	}
+	s.sched.TriggerCheck()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// parseQuery 解析查询参数：source、read、archived、starred、snoozed、since、until（毫秒）、limit、offset
+func parseQuery(values url.Values) (types.NotificationQuery, error) {
+	query := types.NotificationQuery{Source: values.Get("source")}
+
+	bools := map[string]**bool{
+		"read":     &query.Read,
+		"archived": &query.Archived,
+		"starred":  &query.Starred,
+		"snoozed":  &query.Snoozed,
+	}
+	for name, field := range bools {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return query, fmt.Errorf("参数 %s 无效: %s", name, value)
+		}
+		*field = &parsed
+	}
+
+	ints := map[string]*int64{
+		"since": &query.Since,
+		"until": &query.Until,
+	}
+	for name, field := range ints {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed < 0 {
+			return query, fmt.Errorf("参数 %s 无效: %s", name, value)
+		}
+		*field = parsed
+	}
+
+	counts := map[string]*int{
+		"limit":  &query.Limit,
+		"offset": &query.Offset,
+	}
+	for name, field := range counts {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return query, fmt.Errorf("参数 %s 无效: %s", name, value)
+		}
+		*field = parsed
+	}
+	return query, nil
+}
+
+// writeJSON 以 JSON 格式写入响应
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.Debugf("写入本机接口响应失败: %v", err)
+	}
+}
+
+// writeError 以 {"error": "..."} 格式写入错误响应
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
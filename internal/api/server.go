@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"notifyme/internal/config"
+	"notifyme/internal/eventbus"
+	"notifyme/internal/logger"
+	"notifyme/internal/scheduler"
+	"notifyme/pkg/types"
+)
+
+const (
+	tokenFileName   = "api_token"     // 访问令牌文件名（位于数据目录）
+	tokenBytes      = 32              // 访问令牌随机字节数
+	shutdownTimeout = 3 * time.Second // 停止服务时等待请求结束的时间
+)
+
+// Server 本机 HTTP 接口，供编辑器插件、状态栏等工具读取通知
+// 只监听 127.0.0.1，所有请求都需要携带访问令牌
+type Server struct {
+	sched       *scheduler.Scheduler
+	token       string
+	events      *broker
+	unsubscribe func() // 取消订阅事件总线
+
+	mu     sync.Mutex
+	cfg    types.APIConfig
+	port   int          // 正在监听的端口，未运行时为 0
+	server *http.Server // 未运行时为 nil
+	cancel context.CancelFunc
+}
+
+// New 创建本机接口，访问令牌保存在数据目录中，首次使用时自动生成
+// 创建后不会立即监听，需要调用 Reconfigure 按配置启动
+func New(sched *scheduler.Scheduler, dataDir string) (*Server, error) {
+	token, err := loadToken(filepath.Join(dataDir, tokenFileName))
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		sched:  sched,
+		token:  token,
+		events: newBroker(),
+	}
+	// 把调度器发布的新通知推送给事件流订阅者
+	s.unsubscribe = eventbus.Subscribe(func(event eventbus.Event) {
+		if notifications, ok := event.Data.([]*types.Notification); ok && event.Name == eventbus.NotificationsNew {
+			s.events.publish(notifications)
+		}
+	})
+	return s, nil
+}
+
+// Reconfigure 按配置启动、停止或重启本机接口，配置未变化时不做任何操作
+func (s *Server) Reconfigure(cfg types.APIConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cfg == s.cfg && (s.server != nil) == cfg.Enabled {
+		return nil
+	}
+	s.cfg = cfg
+	s.stopLocked()
+	if !cfg.Enabled {
+		return nil
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = config.DefaultAPIPort
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return fmt.Errorf("本机接口监听端口 %d 失败: %w", port, err)
+	}
+
+	// 停止服务时取消 baseCtx，结束所有事件流
+	baseCtx, cancel := context.WithCancel(context.Background())
+	server := &http.Server{
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	s.server = server
+	s.cancel = cancel
+	s.port = port
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("本机接口异常退出: %v", err)
+		}
+	}()
+	logger.Infof("本机接口已启动: %s", s.urlLocked())
+	return nil
+}
+
+// Close 停止本机接口并取消订阅事件总线
+func (s *Server) Close() {
+	s.unsubscribe()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+// stopLocked 停止正在运行的服务（调用方需持有 s.mu）
+func (s *Server) stopLocked() {
+	if s.server == nil {
+		return
+	}
+	s.cancel()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		logger.Warnf("停止本机接口超时: %v", err)
+		s.server.Close()
+	}
+	logger.Info("本机接口已停止")
+	s.server = nil
+	s.cancel = nil
+	s.port = 0
+}
+
+// URL 获取本机接口地址，未运行时返回空字符串
+func (s *Server) URL() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.urlLocked()
+}
+
+// urlLocked 获取本机接口地址（调用方需持有 s.mu）
+func (s *Server) urlLocked() string {
+	if s.server == nil {
+		return ""
+	}
+	return fmt.Sprintf("http://127.0.0.1:%d", s.port)
+}
+
+// Token 获取访问令牌
+func (s *Server) Token() string {
+	return s.token
+}
+
+// authorize 检查请求来源和访问令牌
+// Host 必须是本机地址，防止 DNS 重绑定攻击让网页访问接口
+// 令牌放在 Authorization: Bearer 头中，事件流也可以用 ?token= 传递（浏览器 EventSource 无法设置请求头）
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !loopbackHost(r.Host) {
+			writeError(w, http.StatusForbidden, "只允许通过 127.0.0.1 或 localhost 访问")
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok && r.URL.Path == eventsPath {
+			token = r.URL.Query().Get("token")
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "访问令牌无效")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loopbackHost 检查 Host 请求头是否为本机地址
+func loopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ReadToken 读取数据目录中保存的访问令牌，供命令行等客户端访问正在运行的本机接口
+func ReadToken(dataDir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dataDir, tokenFileName))
+	if err != nil {
+		return "", fmt.Errorf("读取本机接口访问令牌失败: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// loadToken 读取访问令牌，文件不存在或内容无效时生成新令牌并保存（只有当前用户可读）
+func loadToken(path string) (string, error) {
+	if data, err := os.ReadFile(path); err == nil {
+		token := strings.TrimSpace(string(data))
+		if decoded, err := hex.DecodeString(token); err == nil && len(decoded) == tokenBytes {
+			return token, nil
+		}
+		logger.Warnf("本机接口访问令牌无效，重新生成")
+	}
+
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成本机接口访问令牌失败: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("保存本机接口访问令牌失败: %w", err)
+	}
+	logger.Infof("已生成本机接口访问令牌: %s", path)
+	return token, nil
+}
@@ -0,0 +1,10 @@
+package assets
+
+import (
+	_ "embed"
+)
+
+// Icon 应用图标（ICO 格式），托盘和 Windows 桌面通知共用
+//
+//go:embed icon.ico
+var Icon []byte
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"notifyme/internal/logger"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+)
+
+// deviceRequestTimeout 设备授权流程中单次请求的超时时间
+const deviceRequestTimeout = 10 * time.Second
+
+// DeviceCode 设备授权的用户码和验证地址
+// 用户在浏览器中打开验证地址并输入用户码，应用同时轮询等待授权完成
+type DeviceCode struct {
+	UserCode        string `json:"user_code"`        // 用户码，如 WDJB-MJHT
+	VerificationURI string `json:"verification_uri"` // 验证地址，如 https://github.com/login/device
+	ExpiresAt       int64  `json:"expires_at"`       // 过期时间（Unix 秒）
+	Interval        int64  `json:"interval"`         // 服务端要求的最小轮询间隔（秒）
+
+	response *oauth2.DeviceAuthResponse
+}
+
+// NewGitHubDeviceAuth 创建使用设备授权流程（OAuth Device Authorization Grant）的 GitHub 认证
+// 只需要 OAuth App 的 Client ID，不需要 Client Secret 和本地回调服务器
+func NewGitHubDeviceAuth(clientID string) *GitHubAuth {
+	return &GitHubAuth{
+		config: &oauth2.Config{
+			ClientID: clientID,
+			Scopes:   []string{"notifications"},
+			Endpoint: github.Endpoint,
+		},
+	}
+}
+
+// RequestDeviceCode 申请设备码，返回需要展示给用户的用户码和验证地址
+func (a *GitHubAuth) RequestDeviceCode(ctx context.Context) (*DeviceCode, error) {
+	if a.config.ClientID == "" {
+		return nil, fmt.Errorf("未配置 GitHub OAuth App 的 Client ID")
+	}
+
+	response, err := a.config.DeviceAuth(withDeviceHTTPClient(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("申请设备码失败: %w", deviceError(err))
+	}
+
+	code := &DeviceCode{
+		UserCode:        response.UserCode,
+		VerificationURI: response.VerificationURI,
+		Interval:        response.Interval,
+		response:        response,
+	}
+	if !response.Expiry.IsZero() {
+		code.ExpiresAt = response.Expiry.Unix()
+	}
+	logger.Infof("GitHub 设备授权: 请在 %s 输入用户码 %s", code.VerificationURI, code.UserCode)
+	return code, nil
+}
+
+// PollDeviceToken 轮询等待用户在浏览器中完成授权，返回 access token
+// 服务端返回 authorization_pending 时继续等待，返回 slow_down 时按规范把轮询间隔增加 5 秒；
+// 用户拒绝授权、用户码过期或 ctx 取消时返回错误
+func (a *GitHubAuth) PollDeviceToken(ctx context.Context, code *DeviceCode) (*oauth2.Token, error) {
+	if code == nil || code.response == nil {
+		return nil, fmt.Errorf("设备码无效，请重新申请")
+	}
+
+	token, err := a.config.DeviceAccessToken(withDeviceHTTPClient(ctx), code.response)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			// DeviceAccessToken 在设备码过期时以 context.DeadlineExceeded 结束
+			return nil, fmt.Errorf("用户码已过期，请重新登录")
+		}
+		return nil, deviceError(err)
+	}
+
+	a.token = token
+	logger.Info("GitHub 设备授权登录成功")
+	return token, nil
+}
+
+// withDeviceHTTPClient 为 oauth2 请求设置带超时的 HTTP 客户端
+func withDeviceHTTPClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: deviceRequestTimeout})
+}
+
+// deviceError 把设备授权流程的错误码转换为可读的错误
+func deviceError(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	if !errors.As(err, &retrieveErr) {
+		return err
+	}
+	switch retrieveErr.ErrorCode {
+	case "access_denied":
+		return fmt.Errorf("用户拒绝了授权")
+	case "expired_token":
+		return fmt.Errorf("用户码已过期，请重新登录")
+	case "device_flow_disabled":
+		return fmt.Errorf("OAuth App 未启用 Device Flow，请在 GitHub 的 OAuth App 设置中启用")
+	case "incorrect_client_credentials":
+		return fmt.Errorf("Client ID 无效")
+	}
+	return err
+}
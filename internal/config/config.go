@@ -1,10 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"notifyme/internal/quiethours"
+	"notifyme/internal/rules"
 	"notifyme/pkg/types"
 
 	"github.com/spf13/viper"
@@ -14,12 +18,31 @@ const (
 	DefaultPollInterval = 60 // 默认轮询间隔 1 分钟
 	DefaultLogLevel     = "debug"
 	ConfigFileName      = "config.json"
+	DefaultAPIPort      = 7979 // 本机接口默认端口
 )
 
 var (
 	globalConfig *types.Config
+	configPath   string // 通过 SetPath 指定的配置文件路径，为空时按默认规则查找
 )
 
+// SetPath 指定配置文件路径（如守护进程的 -config 参数），需要在 Load 之前调用
+func SetPath(path string) {
+	configPath = path
+}
+
+// DefaultSinks 默认通知渠道（仅桌面通知）
+func DefaultSinks() []types.SinkConfig {
+	return []types.SinkConfig{
+		{Name: "desktop", Type: "desktop", Enabled: true},
+	}
+}
+
+// DefaultDigest 默认汇总提醒配置（一次超过 5 条新通知时合并）
+func DefaultDigest() types.DigestConfig {
+	return types.DigestConfig{Enabled: true, Threshold: 5}
+}
+
 // Load 加载配置文件
 func Load() (*types.Config, error) {
 	configPath := getConfigPath()
@@ -31,7 +54,14 @@ func Load() (*types.Config, error) {
 	viper.SetDefault("poll_interval", DefaultPollInterval)
 	viper.SetDefault("log_level", DefaultLogLevel)
 	viper.SetDefault("github.token", "")
+	viper.SetDefault("github.client_id", "")
 	viper.SetDefault("ld246.token", "")
+	viper.SetDefault("sinks", DefaultSinks())
+	viper.SetDefault("sources", map[string]types.SourceConfig{})
+	viper.SetDefault("rules", []types.Rule{})
+	viper.SetDefault("quiet_hours", types.QuietHoursConfig{})
+	viper.SetDefault("digest", DefaultDigest())
+	viper.SetDefault("api", types.APIConfig{})
 
 	// 如果配置文件不存在，创建默认配置
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -60,7 +90,27 @@ func Load() (*types.Config, error) {
 	config.LogLevel = viper.GetString("log_level")
 	// 直接读取嵌套字段的值（viper 的 Unmarshal 可能不会正确填充嵌套结构）
 	config.GitHub.Token = viper.GetString("github.token")
+	config.GitHub.ClientID = viper.GetString("github.client_id")
 	config.Ld246.Token = viper.GetString("ld246.token")
+	// 结构体切片通过 JSON 中转解析，保证按 json 标签映射字段
+	if err := decodeKey("sinks", &config.Sinks); err != nil {
+		return nil, fmt.Errorf("解析通知渠道配置失败: %w", err)
+	}
+	if err := decodeKey("sources", &config.Sources); err != nil {
+		return nil, fmt.Errorf("解析数据源配置失败: %w", err)
+	}
+	if err := decodeKey("rules", &config.Rules); err != nil {
+		return nil, fmt.Errorf("解析通知规则失败: %w", err)
+	}
+	if err := decodeKey("quiet_hours", &config.QuietHours); err != nil {
+		return nil, fmt.Errorf("解析勿扰时段失败: %w", err)
+	}
+	if err := decodeKey("digest", &config.Digest); err != nil {
+		return nil, fmt.Errorf("解析汇总提醒配置失败: %w", err)
+	}
+	if err := decodeKey("api", &config.API); err != nil {
+		return nil, fmt.Errorf("解析本机接口配置失败: %w", err)
+	}
 
 	// 验证配置
 	if err := validateConfig(config); err != nil {
@@ -82,7 +132,14 @@ func Save(config *types.Config) error {
 	viper.Set("poll_interval", config.PollInterval)
 	viper.Set("log_level", config.LogLevel)
 	viper.Set("github.token", config.GitHub.Token)
+	viper.Set("github.client_id", config.GitHub.ClientID)
 	viper.Set("ld246.token", config.Ld246.Token)
+	viper.Set("sinks", config.Sinks)
+	viper.Set("sources", config.Sources)
+	viper.Set("rules", config.Rules)
+	viper.Set("quiet_hours", config.QuietHours)
+	viper.Set("digest", config.Digest)
+	viper.Set("api", config.API)
 
 	if err := viper.WriteConfigAs(configPath); err != nil {
 		return fmt.Errorf("保存配置文件失败: %w", err)
@@ -101,16 +158,33 @@ func Get() *types.Config {
 			LogLevel:     DefaultLogLevel,
 			GitHub:       types.GitHubAuth{Token: ""},
 			Ld246:        types.Ld246Config{Token: ""},
+			Sinks:        DefaultSinks(),
+			Digest:       DefaultDigest(),
 		}
 	}
 	return globalConfig
 }
 
+// decodeKey 将 viper 中指定键的值解析到 out
+// viper 默认按 mapstructure 标签解码，这里先序列化为 JSON 再按 json 标签解析
+func decodeKey(key string, out interface{}) error {
+	data, err := json.Marshal(viper.Get(key))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
 // validateConfig 验证配置
 func validateConfig(config *types.Config) error {
 	if config.PollInterval < 10 {
 		return fmt.Errorf("轮询间隔不能小于 10 秒")
 	}
+	for name, source := range config.Sources {
+		if source.PollInterval != 0 && source.PollInterval < 10 {
+			return fmt.Errorf("数据源 %s 的轮询间隔不能小于 10 秒", name)
+		}
+	}
 
 	validLogLevels := map[string]bool{
 		"debug": true,
@@ -122,11 +196,88 @@ func validateConfig(config *types.Config) error {
 		return fmt.Errorf("无效的日志级别: %s", config.LogLevel)
 	}
 
+	sinkNames := make(map[string]bool, len(config.Sinks)) // 渠道名称 -> 是否启用
+	validSinkTypes := map[string]bool{
+		"desktop": true,
+		"webhook": true,
+		"file":    true,
+	}
+	for i, sink := range config.Sinks {
+		if !validSinkTypes[sink.Type] {
+			return fmt.Errorf("第 %d 个通知渠道类型无效: %s", i+1, sink.Type)
+		}
+		if sink.Type == "webhook" && sink.Enabled && sink.URL == "" {
+			return fmt.Errorf("通知渠道 %s 未设置推送地址", sink.Name)
+		}
+		if sink.Type == "file" && sink.Enabled && sink.Path == "" {
+			return fmt.Errorf("通知渠道 %s 未设置输出文件路径", sink.Name)
+		}
+		if sink.Urgency != "" && sink.Urgency != "low" && sink.Urgency != "normal" && sink.Urgency != "critical" {
+			return fmt.Errorf("通知渠道 %s 紧急程度无效: %s", sink.Name, sink.Urgency)
+		}
+		// 没有名称的渠道以类型作为名称，规则和状态都按名称区分渠道
+		name := sink.Name
+		if name == "" {
+			name = sink.Type
+		}
+		if _, ok := sinkNames[name]; ok {
+			return fmt.Errorf("通知渠道名称重复: %s（没有名称的渠道以类型作为名称）", name)
+		}
+		sinkNames[name] = sink.Enabled
+	}
+
+	if err := rules.Validate(config.Rules); err != nil {
+		return err
+	}
+	for _, rule := range config.Rules {
+		enabled := false
+		for _, sink := range rule.Sinks {
+			sinkEnabled, ok := sinkNames[sink]
+			if !ok {
+				return fmt.Errorf("规则 %s 引用了不存在的通知渠道: %s", rule.Name, sink)
+			}
+			enabled = enabled || sinkEnabled
+		}
+		// 只投递到未启用渠道的规则会让匹配的通知无法投递
+		if rule.Enabled && len(rule.Sinks) > 0 && !enabled {
+			return fmt.Errorf("规则 %s 指定的通知渠道都未启用: %s", rule.Name, strings.Join(rule.Sinks, ", "))
+		}
+	}
+
+	if err := quiethours.Validate(config.QuietHours); err != nil {
+		return err
+	}
+
+	if config.Digest.Threshold < 0 {
+		return fmt.Errorf("汇总提醒条数不能小于 0")
+	}
+	if config.Digest.Window < 0 || config.Digest.Window > 3600 {
+		return fmt.Errorf("汇总提醒窗口必须在 0 到 3600 秒之间")
+	}
+
+	if config.API.Port < 0 || config.API.Port > 65535 {
+		return fmt.Errorf("无效的本机接口端口: %d", config.API.Port)
+	}
+
 	return nil
 }
 
+// Path 获取当前使用的配置文件路径
+func Path() string {
+	return getConfigPath()
+}
+
+// Validate 检查配置是否有效（Load 和 Save 时会自动检查）
+func Validate(config *types.Config) error {
+	return validateConfig(config)
+}
+
 // getConfigPath 获取配置文件路径
 func getConfigPath() string {
+	if configPath != "" {
+		return configPath
+	}
+
 	// 优先使用当前目录
 	configPath := filepath.Join(".", ConfigFileName)
 	if _, err := os.Stat(configPath); err == nil {
@@ -154,12 +305,21 @@ func createDefaultConfig(configPath string) error {
 	defaultConfig := &types.Config{
 		PollInterval: DefaultPollInterval,
 		LogLevel:     DefaultLogLevel,
+		Sinks:        DefaultSinks(),
+		Digest:       DefaultDigest(),
 	}
 
 	viper.Set("poll_interval", defaultConfig.PollInterval)
 	viper.Set("log_level", defaultConfig.LogLevel)
 	viper.Set("github.token", "")
+	viper.Set("github.client_id", "")
 	viper.Set("ld246.token", "")
+	viper.Set("sinks", defaultConfig.Sinks)
+	viper.Set("sources", map[string]types.SourceConfig{})
+	viper.Set("rules", []types.Rule{})
+	viper.Set("quiet_hours", types.QuietHoursConfig{})
+	viper.Set("digest", defaultConfig.Digest)
+	viper.Set("api", defaultConfig.API)
 
 	return viper.WriteConfigAs(configPath)
 }
@@ -0,0 +1,203 @@
+package deeplink
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"notifyme/pkg/types"
+)
+
+// Scheme 应用链接的协议名
+const Scheme = "notifyme"
+
+// 链接动作
+//
+//	notifyme://open               打开主界面
+//	notifyme://open/<id>          在浏览器中打开通知并标记为已读
+//	notifyme://read/<id>          标记为已读
+//	notifyme://snooze/<id>?d=1h   稍后提醒，d 为时长（如 30m、2h、1d），默认 1 小时
+//	notifyme://mute-thread/<id>   静音通知所在的会话
+//	notifyme://done/<id>          标记为完成
+//	notifyme://reply/<id>         在浏览器中打开回复页面
+//
+// 本程序生成的链接（如 Windows 通知上的按钮）带有 t 参数，值为进程启动时生成的随机令牌，见 Sign
+const (
+	ActionOpen       = "open"
+	ActionRead       = "read"
+	ActionSnooze     = "snooze"
+	ActionMuteThread = "mute-thread"
+	ActionDone       = "done"
+	ActionReply      = "reply"
+)
+
+// notificationActions 链接动作对应的通知动作
+var notificationActions = map[string]string{
+	ActionOpen:       types.ActionOpen,
+	ActionRead:       types.ActionMarkRead,
+	ActionSnooze:     types.ActionSnooze,
+	ActionMuteThread: types.ActionMuteThread,
+	ActionDone:       types.ActionMarkDone,
+	ActionReply:      types.ActionReply,
+}
+
+// DefaultSnooze 稍后提醒链接没有指定时长时的默认时长（与通知动作的默认时长相同）
+const DefaultSnooze = time.Hour
+
+// token 本进程生成的链接携带的令牌，用于区分本程序发出的链接和其他网页或程序打开的链接
+var token = newToken()
+
+// Link 解析后的应用链接
+type Link struct {
+	Action   string        // 动作
+	ID       string        // 通知 ID，打开主界面时为空
+	Duration time.Duration // 稍后提醒的时长（只用于 snooze）
+	Token    string        // 链接携带的令牌，为空表示没有
+}
+
+// Parse 解析应用链接
+func Parse(raw string) (*Link, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("无效的链接: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, Scheme) {
+		return nil, fmt.Errorf("不是 %s:// 链接: %s", Scheme, raw)
+	}
+
+	// 某些系统在打开链接时会在末尾加上 /
+	link := &Link{
+		Action: strings.ToLower(u.Host),
+		ID:     strings.Trim(u.Path, "/"),
+		Token:  u.Query().Get("t"),
+	}
+	switch link.Action {
+	case ActionOpen:
+		return link, nil
+	case ActionRead, ActionMuteThread, ActionDone, ActionReply:
+	case ActionSnooze:
+		link.Duration = DefaultSnooze
+		if value := u.Query().Get("d"); value != "" {
+			if link.Duration, err = ParseDuration(value); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("未知的链接动作: %s", link.Action)
+	}
+
+	if link.ID == "" {
+		return nil, fmt.Errorf("链接缺少通知 ID: %s", raw)
+	}
+	return link, nil
+}
+
+// String 生成链接
+func (l *Link) String() string {
+	s := Scheme + "://" + l.Action
+	if l.ID != "" {
+		s += "/" + url.PathEscape(l.ID)
+	}
+	query := url.Values{}
+	if l.Action == ActionSnooze && l.Duration > 0 {
+		query.Set("d", formatDuration(l.Duration))
+	}
+	if l.Token != "" {
+		query.Set("t", l.Token)
+	}
+	if len(query) > 0 {
+		s += "?" + query.Encode()
+	}
+	return s
+}
+
+// Trusted 链接是否由本进程生成（携带本进程的令牌）
+// 程序重启后之前生成的链接不再可信
+func (l *Link) Trusted() bool {
+	return l.Token != "" && subtle.ConstantTimeCompare([]byte(l.Token), []byte(token)) == 1
+}
+
+// Sign 为本程序生成的链接加上本进程的令牌，无法解析的链接原样返回
+func Sign(raw string) string {
+	link, err := Parse(raw)
+	if err != nil {
+		return raw
+	}
+	link.Token = token
+	return link.String()
+}
+
+// NotificationAction 链接对应的通知动作标识（types.ActionOpen 等）
+func (l *Link) NotificationAction() string {
+	return notificationActions[l.Action]
+}
+
+// ForAction 生成执行通知动作的链接，不支持的动作返回空字符串
+// 稍后提醒使用默认时长
+func ForAction(id, actionID string) string {
+	for action, notificationAction := range notificationActions {
+		if notificationAction == actionID {
+			link := &Link{Action: action, ID: id}
+			if action == ActionSnooze {
+				link.Duration = DefaultSnooze
+			}
+			return link.String()
+		}
+	}
+	return ""
+}
+
+// Open 生成在浏览器中打开通知的链接，id 为空时打开主界面
+func Open(id string) string {
+	return (&Link{Action: ActionOpen, ID: id}).String()
+}
+
+// newToken 生成随机令牌
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("deeplink: 生成令牌失败: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// ParseDuration 解析稍后提醒的时长，除 time.ParseDuration 支持的格式外还支持按天（如 1d），时长必须大于 0
+func ParseDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("无效的时长: %s", value)
+		}
+		duration = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("无效的时长: %s", value)
+		}
+		duration = d
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("时长必须大于 0: %s", value)
+	}
+	return duration, nil
+}
+
+// formatDuration 把时长格式化为链接中使用的简短形式，如 1d、2h、30m
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return strconv.Itoa(int(d/(24*time.Hour))) + "d"
+	case d%time.Hour == 0:
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	case d%time.Minute == 0:
+		return strconv.Itoa(int(d/time.Minute)) + "m"
+	default:
+		return d.String()
+	}
+}
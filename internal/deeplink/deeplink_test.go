@@ -0,0 +1,139 @@
+package deeplink
+
+import (
+	"testing"
+	"time"
+
+	"notifyme/pkg/types"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Link
+		str     string // String() 的结果，为空表示与 raw 相同
+		wantErr bool
+	}{
+		{name: "打开主界面", raw: "notifyme://open", want: Link{Action: ActionOpen}},
+		{name: "打开通知", raw: "notifyme://open/github_1_2", want: Link{Action: ActionOpen, ID: "github_1_2"}},
+		{name: "末尾的 /", raw: "notifyme://read/ld246_at_1/", want: Link{Action: ActionRead, ID: "ld246_at_1"}, str: "notifyme://read/ld246_at_1"},
+		{name: "打开主界面末尾的 /", raw: "notifyme://open/", want: Link{Action: ActionOpen}, str: "notifyme://open"},
+		{name: "协议和动作大写", raw: "NotifyMe://DONE/github_1_2", want: Link{Action: ActionDone, ID: "github_1_2"}, str: "notifyme://done/github_1_2"},
+		{name: "ID 中的特殊字符", raw: "notifyme://reply/a%2Fb%20c", want: Link{Action: ActionReply, ID: "a/b c"}},
+		{name: "静音会话", raw: "notifyme://mute-thread/github_1_2", want: Link{Action: ActionMuteThread, ID: "github_1_2"}},
+
+		{name: "稍后提醒默认时长", raw: "notifyme://snooze/x", want: Link{Action: ActionSnooze, ID: "x", Duration: DefaultSnooze}, str: "notifyme://snooze/x?d=1h"},
+		{name: "按天", raw: "notifyme://snooze/x?d=1d", want: Link{Action: ActionSnooze, ID: "x", Duration: 24 * time.Hour}},
+		{name: "按分钟", raw: "notifyme://snooze/x?d=30m", want: Link{Action: ActionSnooze, ID: "x", Duration: 30 * time.Minute}},
+		{name: "超过一小时的分钟数", raw: "notifyme://snooze/x?d=90m", want: Link{Action: ActionSnooze, ID: "x", Duration: 90 * time.Minute}},
+		{name: "携带令牌", raw: "notifyme://read/x?t=abc", want: Link{Action: ActionRead, ID: "x", Token: "abc"}},
+		{name: "时长和令牌", raw: "notifyme://snooze/x?t=abc&d=2h", want: Link{Action: ActionSnooze, ID: "x", Duration: 2 * time.Hour, Token: "abc"}, str: "notifyme://snooze/x?d=2h&t=abc"},
+		{name: "组合时长", raw: "notifyme://snooze/x?d=1h30m", want: Link{Action: ActionSnooze, ID: "x", Duration: 90 * time.Minute}, str: "notifyme://snooze/x?d=90m"},
+
+		{name: "无效的时长", raw: "notifyme://snooze/x?d=soon", wantErr: true},
+		{name: "无效的天数", raw: "notifyme://snooze/x?d=xd", wantErr: true},
+		{name: "负数时长", raw: "notifyme://snooze/x?d=-1h", wantErr: true},
+		{name: "负数天数", raw: "notifyme://snooze/x?d=-1d", wantErr: true},
+		{name: "时长为 0", raw: "notifyme://snooze/x?d=0", wantErr: true},
+		{name: "缺少 ID", raw: "notifyme://read", wantErr: true},
+		{name: "只有 /", raw: "notifyme://done/", wantErr: true},
+		{name: "稍后提醒缺少 ID", raw: "notifyme://snooze?d=1h", wantErr: true},
+		{name: "未知动作", raw: "notifyme://delete/x", wantErr: true},
+		{name: "其他协议", raw: "https://read/x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, err := Parse(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %+v, want error", tt.raw, link)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) 失败: %v", tt.raw, err)
+			}
+			if *link != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, *link, tt.want)
+			}
+
+			want := tt.str
+			if want == "" {
+				want = tt.raw
+			}
+			if got := link.String(); got != want {
+				t.Errorf("String() = %q, want %q", got, want)
+			}
+
+			// 生成的链接再次解析得到相同结果
+			again, err := Parse(link.String())
+			if err != nil || *again != *link {
+				t.Errorf("Parse(String()) = %+v, %v, want %+v", again, err, *link)
+			}
+		})
+	}
+}
+
+func TestForAction(t *testing.T) {
+	tests := []struct {
+		action string
+		want   string
+	}{
+		{types.ActionOpen, "notifyme://open/x"},
+		{types.ActionMarkRead, "notifyme://read/x"},
+		{types.ActionMarkDone, "notifyme://done/x"},
+		{types.ActionMuteThread, "notifyme://mute-thread/x"},
+		{types.ActionSnooze, "notifyme://snooze/x?d=1h"},
+		{types.ActionReply, "notifyme://reply/x"},
+		{"unknown", ""},
+	}
+	for _, tt := range tests {
+		got := ForAction("x", tt.action)
+		if got != tt.want {
+			t.Errorf("ForAction(%q) = %q, want %q", tt.action, got, tt.want)
+			continue
+		}
+		if got == "" {
+			continue
+		}
+		link, err := Parse(got)
+		if err != nil || link.NotificationAction() != tt.action {
+			t.Errorf("Parse(%q).NotificationAction() = %v, %v, want %q", got, link, err, tt.action)
+		}
+	}
+}
+
+func TestSign(t *testing.T) {
+	signed := Sign("notifyme://snooze/x?d=30m")
+	link, err := Parse(signed)
+	if err != nil {
+		t.Fatalf("Parse(%q) 失败: %v", signed, err)
+	}
+	if !link.Trusted() || link.Duration != 30*time.Minute {
+		t.Errorf("Sign 生成的链接 = %+v, 应可信且保留时长", *link)
+	}
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{"没有令牌", "notifyme://read/x"},
+		{"伪造的令牌", "notifyme://read/x?t=0123456789abcdef0123456789abcdef"},
+		{"空令牌", "notifyme://read/x?t="},
+	}
+	for _, tt := range tests {
+		link, err := Parse(tt.raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if link.Trusted() {
+			t.Errorf("%s: Parse(%q).Trusted() = true", tt.name, tt.raw)
+		}
+	}
+
+	if got := Sign("https://example.com"); got != "https://example.com" {
+		t.Errorf("Sign 无法解析的链接 = %q, want 原样返回", got)
+	}
+}
@@ -0,0 +1,111 @@
+//go:build linux
+
+package deeplink
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"notifyme/internal/logger"
+)
+
+// desktopFileName 注册链接处理程序的 .desktop 文件名
+const desktopFileName = "notifyme.desktop"
+
+// Register 把当前程序注册为 notifyme:// 链接的处理程序
+// 在 ~/.local/share/applications 中写入 .desktop 文件，并通过 xdg-mime 设为默认处理程序；内容未变化时不做任何操作
+func Register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("获取程序路径失败: %w", err)
+	}
+	dir, err := applicationsDir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, desktopFileName)
+	entry := DesktopEntry(exe)
+	if current, err := os.ReadFile(path); err == nil && string(current) == entry {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录 %s 失败: %w", dir, err)
+	}
+	if err := os.WriteFile(path, []byte(entry), 0644); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", path, err)
+	}
+
+	if err := runIfExists("xdg-mime", "default", desktopFileName, "x-scheme-handler/"+Scheme); err != nil {
+		return fmt.Errorf("设置默认链接处理程序失败: %w", err)
+	}
+	if err := runIfExists("update-desktop-database", dir); err != nil {
+		logger.Debugf("更新桌面数据库失败: %v", err)
+	}
+	logger.Infof("已注册 %s:// 链接处理程序: %s", Scheme, path)
+	return nil
+}
+
+// Unregister 删除注册的 .desktop 文件
+func Unregister() error {
+	dir, err := applicationsDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, desktopFileName)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除 %s 失败: %w", path, err)
+	}
+	if err := runIfExists("update-desktop-database", dir); err != nil {
+		logger.Debugf("更新桌面数据库失败: %v", err)
+	}
+	return nil
+}
+
+// DesktopEntry 生成处理 notifyme:// 链接的 .desktop 文件内容（打包时也可以直接使用）
+func DesktopEntry(exe string) string {
+	return fmt.Sprintf(`[Desktop Entry]
+Type=Application
+Name=NotifyMe
+Comment=消息通知
+Exec=%s %%u
+Terminal=false
+Categories=Network;Utility;
+MimeType=x-scheme-handler/%s;
+`, quoteExec(exe), Scheme)
+}
+
+// applicationsDir 用户级 .desktop 文件目录（$XDG_DATA_HOME/applications）
+func applicationsDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "applications"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户目录失败: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "applications"), nil
+}
+
+// quoteExec 按 Desktop Entry 规范给 Exec 中的程序路径加引号
+// 引号内的保留字符先用反斜杠转义，整个值再按字符串规则把反斜杠转义一次
+func quoteExec(path string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`)
+	quoted := `"` + replacer.Replace(path) + `"`
+	return strings.ReplaceAll(quoted, `\`, `\\`)
+}
+
+// runIfExists 执行命令，命令不存在时直接返回
+func runIfExists(name string, args ...string) error {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil
+	}
+	if output, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package deeplink
+
+// Register 其他系统不在运行时注册链接处理程序（macOS 由打包时 Info.plist 中的 CFBundleURLTypes 声明）
+func Register() error {
+	return nil
+}
+
+// Unregister 其他系统不在运行时注册链接处理程序
+func Unregister() error {
+	return nil
+}
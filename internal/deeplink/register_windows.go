@@ -0,0 +1,73 @@
+//go:build windows
+
+package deeplink
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+	"notifyme/internal/logger"
+)
+
+// classesKey 当前用户的 notifyme:// 协议注册表项
+const classesKey = `Software\Classes\` + Scheme
+
+// Register 把当前程序注册为 notifyme:// 链接的处理程序（写入 HKEY_CURRENT_USER，不需要管理员权限）
+// 系统通知上的按钮通过该链接启动程序，再由单实例转发给正在运行的实例
+func Register() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("获取程序路径失败: %w", err)
+	}
+	command := fmt.Sprintf(`"%s" "%%1"`, exe)
+
+	if current, err := readCommand(); err == nil && current == command {
+		return nil
+	}
+
+	key, _, err := registry.CreateKey(registry.CURRENT_USER, classesKey, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("创建注册表项失败: %w", err)
+	}
+	defer key.Close()
+	if err := key.SetStringValue("", "URL:NotifyMe Protocol"); err != nil {
+		return fmt.Errorf("写入注册表失败: %w", err)
+	}
+	if err := key.SetStringValue("URL Protocol", ""); err != nil {
+		return fmt.Errorf("写入注册表失败: %w", err)
+	}
+
+	commandKey, _, err := registry.CreateKey(registry.CURRENT_USER, classesKey+`\shell\open\command`, registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("创建注册表项失败: %w", err)
+	}
+	defer commandKey.Close()
+	if err := commandKey.SetStringValue("", command); err != nil {
+		return fmt.Errorf("写入注册表失败: %w", err)
+	}
+
+	logger.Infof("已注册 %s:// 链接处理程序: %s", Scheme, exe)
+	return nil
+}
+
+// Unregister 删除注册表中的 notifyme:// 协议（注册表项需要从最深一层开始删除）
+func Unregister() error {
+	for _, path := range []string{`\shell\open\command`, `\shell\open`, `\shell`, ``} {
+		if err := registry.DeleteKey(registry.CURRENT_USER, classesKey+path); err != nil && err != registry.ErrNotExist {
+			return fmt.Errorf("删除注册表项失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// readCommand 读取当前注册的启动命令
+func readCommand() (string, error) {
+	key, err := registry.OpenKey(registry.CURRENT_USER, classesKey+`\shell\open\command`, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+	value, _, err := key.GetStringValue("")
+	return value, err
+}
@@ -0,0 +1,81 @@
+package eventbus
+
+import (
+	"sync"
+)
+
+// 事件名称，桌面版原样作为 Wails 运行时事件名转发给前端
+const (
+	NotificationsNew     = "notifications:new"     // 收到新通知或通知有更新，数据为 []*types.Notification
+	NotificationsChanged = "notifications:changed" // 通知的本地状态（已读、归档、星标等）变化，数据为通知 ID 列表，批量修改时为空
+	CheckStarted         = "check:started"         // 开始检查数据源，数据为 CheckEvent
+	CheckFinished        = "check:finished"        // 数据源检查结束，数据为 CheckEvent
+	HealthChanged        = "source:health"         // 数据源健康状态变化，数据为 HealthEvent
+	ConfigReloaded       = "config:reloaded"       // 配置已重新加载，没有数据
+	GitHubLogin          = "github:login"          // GitHub 设备授权登录结束，数据为 LoginEvent
+)
+
+// Event 一条事件
+type Event struct {
+	Name string
+	Data interface{}
+}
+
+// CheckEvent 数据源检查事件
+type CheckEvent struct {
+	Source string `json:"source"`
+	New    int    `json:"new"`   // 新通知条数（只在检查结束时有效）
+	Error  string `json:"error"` // 检查失败原因，成功时为空
+}
+
+// HealthEvent 数据源健康状态变化事件
+type HealthEvent struct {
+	Source string `json:"source"`
+	Health string `json:"health"` // ok、degraded 或 down
+	Error  string `json:"error"`  // 最近一次失败原因，恢复正常时为空
+}
+
+// LoginEvent 设备授权登录结束事件
+type LoginEvent struct {
+	Error string `json:"error"` // 登录失败原因，成功时为空（token 已保存到配置）
+}
+
+// Handler 事件处理函数，在发布事件的 goroutine 中同步调用，不能阻塞
+type Handler func(event Event)
+
+var (
+	mu       sync.RWMutex
+	handlers = make(map[int]Handler)
+	nextID   int
+)
+
+// Subscribe 订阅所有事件，返回取消订阅的函数
+func Subscribe(handler Handler) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	id := nextID
+	nextID++
+	handlers[id] = handler
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(handlers, id)
+	}
+}
+
+// Publish 发布事件，没有订阅者时直接丢弃
+func Publish(name string, data interface{}) {
+	mu.RLock()
+	subscribers := make([]Handler, 0, len(handlers))
+	for _, handler := range handlers {
+		subscribers = append(subscribers, handler)
+	}
+	mu.RUnlock()
+
+	event := Event{Name: name, Data: data}
+	for _, handler := range subscribers {
+		handler(event)
+	}
+}
@@ -10,9 +10,16 @@ import (
 )
 
 var (
-	logger *logrus.Logger
+	logger  *logrus.Logger
+	console io.Writer = os.Stdout // 控制台日志输出
 )
 
+// SetConsole 设置控制台日志输出（默认为标准输出），需要在 Init 之前调用
+// 命令行输出结果时可以设置为标准错误或 io.Discard，避免日志混入结果（文件日志不受影响）
+func SetConsole(w io.Writer) {
+	console = w
+}
+
 // fileLogWriter 用于将日志写入文件，使用无颜色格式
 type fileLogWriter struct {
 	file      *os.File
@@ -114,11 +121,11 @@ func Init(logLevel string, logToFile bool) error {
 		// 使用安全的 MultiWriter，即使 stdout 不可用（GUI 模式）也能正常写入文件
 		// safeMultiWriter 会尝试写入所有 writer，即使某个失败也继续
 		safeWriter := &safeMultiWriter{
-			writers: []io.Writer{os.Stdout, fileWriter},
+			writers: []io.Writer{console, fileWriter},
 		}
 		logger.SetOutput(safeWriter)
 	} else {
-		logger.SetOutput(os.Stdout)
+		logger.SetOutput(console)
 	}
 
 	return nil
@@ -0,0 +1,38 @@
+package monitor
+
+import "notifyme/pkg/types"
+
+// notificationActions 生成通知上的动作，按常用程度排列（部分桌面通知最多只显示前几个按钮）
+// 标记已读、标记完成、稍后提醒和静音会话都先在本地执行，站点支持时再同步到站点
+// replyURL 为空表示不能回复，markDone 表示站点上有“完成”的概念
+func notificationActions(replyURL string, markDone bool) []types.NotificationAction {
+	actions := []types.NotificationAction{{ID: types.ActionOpen, Label: "打开"}}
+	if replyURL != "" {
+		actions = append(actions, types.NotificationAction{ID: types.ActionReply, Label: "回复", URL: replyURL})
+	}
+	actions = append(actions, types.NotificationAction{ID: types.ActionMarkRead, Label: "标记已读"})
+	if markDone {
+		actions = append(actions, types.NotificationAction{ID: types.ActionMarkDone, Label: "完成"})
+	}
+	return append(actions,
+		types.NotificationAction{ID: types.ActionSnooze, Label: "稍后提醒"},
+		types.NotificationAction{ID: types.ActionMuteThread, Label: "静音会话"},
+	)
+}
+
+// githubActions GitHub 通知的动作：支持标记为完成，Issue、PR 和讨论可以跳到评论框回复
+func githubActions(subjectType, link string) []types.NotificationAction {
+	replyURL := ""
+	switch subjectType {
+	case "Issue", "PullRequest", "Discussion":
+		if link != "" {
+			replyURL = link + "#new_comment_field"
+		}
+	}
+	return notificationActions(replyURL, true)
+}
+
+// ld246Actions ld246 通知的动作：有链接的通知可以打开帖子回复
+func ld246Actions(link string) []types.NotificationAction {
+	return notificationActions(link, false)
+}
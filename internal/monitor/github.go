@@ -1,11 +1,14 @@
 package monitor
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,7 +23,14 @@ type GitHubMonitor struct {
 	token        string
 	httpClient   *http.Client
 	lastModified time.Time    // 上次查询时间，用于优化轮询
-	mu           sync.RWMutex // 保护 lastModified 的互斥锁
+	pollHint     PollHint     // 根据响应头得到的轮询建议
+	mu           sync.RWMutex // 保护 lastModified 和 pollHint 的互斥锁
+}
+
+func init() {
+	Register("github", func(cfg *types.Config) Source {
+		return NewGitHubMonitor(cfg.GitHub.Token)
+	})
 }
 
 // NewGitHubMonitor 创建新的 GitHub 监控器
@@ -34,15 +44,30 @@ func NewGitHubMonitor(token string) *GitHubMonitor {
 	}
 }
 
+// Name 实现 Source 接口
+func (m *GitHubMonitor) Name() string {
+	return "github"
+}
+
+// Capabilities 实现 Source 接口
+func (m *GitHubMonitor) Capabilities() []Capability {
+	return []Capability{CapabilityIncremental, CapabilityMarkRead, CapabilityMarkAllRead, CapabilityMarkDone, CapabilityMuteThread}
+}
+
+// Fetch 实现 Source 接口，获取 GitHub 新通知
+func (m *GitHubMonitor) Fetch(ctx context.Context) ([]*types.Notification, error) {
+	return m.FetchNotifications(ctx)
+}
+
 // FetchNotifications 获取 GitHub 通知
 // 支持 since 查询参数和 Last-Modified 头优化
-func (m *GitHubMonitor) FetchNotifications() ([]*types.Notification, error) {
-	return m.FetchNotificationsSince(time.Time{})
+func (m *GitHubMonitor) FetchNotifications(ctx context.Context) ([]*types.Notification, error) {
+	return m.FetchNotificationsSince(ctx, time.Time{})
 }
 
 // FetchNotificationsSince 获取自指定时间之后的 GitHub 通知
 // 如果 since 为零值，则使用上次查询时间（Last-Modified）
-func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notification, error) {
+func (m *GitHubMonitor) FetchNotificationsSince(ctx context.Context, since time.Time) ([]*types.Notification, error) {
 	if m.token == "" {
 		return nil, fmt.Errorf("GitHub token 未设置")
 	}
@@ -75,7 +100,7 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 	apiURL.RawQuery = query.Encode()
 	reqURL := apiURL.String()
 
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -95,6 +120,7 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 		return nil, fmt.Errorf("请求失败: %w", err)
 	}
 	defer resp.Body.Close()
+	m.updatePollHint(resp)
 
 	// 处理 304 Not Modified 响应（没有新通知）
 	if resp.StatusCode == http.StatusNotModified {
@@ -129,11 +155,6 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 		}
 	}
 
-	// 记录 X-Poll-Interval 响应头（轮询间隔建议）
-	if pollInterval := resp.Header.Get("X-Poll-Interval"); pollInterval != "" {
-		logger.Debugf("GitHub API 响应头 X-Poll-Interval: %s 秒", pollInterval)
-	}
-
 	// 格式化并输出原始响应
 	// var rawJSON interface{}
 	// if err := json.Unmarshal(bodyBytes, &rawJSON); err == nil {
@@ -151,13 +172,19 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 		ID         string `json:"id"`
 		Repository struct {
 			FullName string `json:"full_name"`
+			Owner    struct {
+				Login     string `json:"login"`
+				AvatarURL string `json:"avatar_url"`
+			} `json:"owner"`
 		} `json:"repository"`
 		Subject struct {
-			Title string `json:"title"`
-			Type  string `json:"type"`
-			URL   string `json:"url"`
+			Title            string `json:"title"`
+			Type             string `json:"type"`
+			URL              string `json:"url"`
+			LatestCommentURL string `json:"latest_comment_url"`
 		} `json:"subject"`
 		Reason    string    `json:"reason"`
+		Unread    bool      `json:"unread"`
 		UpdatedAt time.Time `json:"updated_at"`
 		URL       string    `json:"url"`
 		HTMLURL   string    `json:"html_url"`
@@ -187,7 +214,7 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 
 		// 将 GitHub API URL 转换为 HTML URL
 		// 使用 Subject.URL 来获取实际指向 issue/PR 的链接，而不是通知线程的链接
-		link := m.convertGitHubAPIToHTML(item.Subject.URL, item.Subject.Type, item.Repository.FullName)
+		link := m.convertGitHubAPIToHTML(ctx, item.Subject.URL, item.Subject.Type, item.Repository.FullName)
 		if link == "" {
 			// 如果 Subject.URL 为空，回退到 HTMLURL（通知线程链接）
 			link = item.HTMLURL
@@ -195,12 +222,25 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 		logger.Debugf("GitHub 通知 #%d: Subject.Type=%s, 转换后的链接=%s (Subject.URL=%s, HTMLURL=%s)", i+1, item.Subject.Type, link, item.Subject.URL, item.HTMLURL)
 
 		notification := &types.Notification{
-			ID:      fmt.Sprintf("github_%s", item.ID),
-			Title:   title,
-			Content: content,
-			Link:    link,
-			Source:  "github",
-			Time:    item.UpdatedAt.Unix(),
+			ID:         fmt.Sprintf("github_%s_%d", item.ID, item.UpdatedAt.Unix()), // 每次更新单独保存，组成会话的时间线
+			ThreadKey:  fmt.Sprintf("github_%s", item.ID),
+			Title:      title,
+			Content:    content,
+			Link:       link,
+			Source:     "github",
+			Repository: item.Repository.FullName,
+			Reason:     item.Reason,
+			Kind:       item.Subject.Type,
+			AvatarURL:  item.Repository.Owner.AvatarURL,
+			Time:       item.UpdatedAt.Unix(),
+			Unread:     item.Unread,
+			Actions:    githubActions(item.Subject.Type, link),
+			Extra: map[string]string{
+				"thread_url":         item.URL,
+				"subject_url":        item.Subject.URL,
+				"latest_comment_url": item.Subject.LatestCommentURL,
+				"owner":              item.Repository.Owner.Login,
+			},
 		}
 		result = append(result, notification)
 	}
@@ -209,8 +249,233 @@ func (m *GitHubMonitor) FetchNotificationsSince(since time.Time) ([]*types.Notif
 	return result, nil
 }
 
+// Test 实现 Tester 接口，请求一条通知检查 token 是否有效且有通知权限
+func (m *GitHubMonitor) Test(ctx context.Context) error {
+	if err := m.doRequest(ctx, "GET", "/notifications?per_page=1", nil); err != nil {
+		return fmt.Errorf("检查 GitHub 连接失败: %w", err)
+	}
+	return nil
+}
+
+// MarkRead 实现 ReadMarker 接口，将通知线程标记为已读
+func (m *GitHubMonitor) MarkRead(ctx context.Context, notification *types.Notification) error {
+	threadID, err := threadIDOf(notification)
+	if err != nil {
+		return err
+	}
+	return m.MarkThreadRead(ctx, threadID)
+}
+
+// MarkDone 实现 DoneMarker 接口，将通知线程标记为完成
+func (m *GitHubMonitor) MarkDone(ctx context.Context, notification *types.Notification) error {
+	threadID, err := threadIDOf(notification)
+	if err != nil {
+		return err
+	}
+	return m.MarkThreadDone(ctx, threadID)
+}
+
+// MuteThread 实现 ThreadMuter 接口，取消订阅通知线程
+func (m *GitHubMonitor) MuteThread(ctx context.Context, notification *types.Notification) error {
+	threadID, err := threadIDOf(notification)
+	if err != nil {
+		return err
+	}
+	return m.IgnoreThread(ctx, threadID)
+}
+
+// MarkAllRead 实现 AllReadMarker 接口，将所有通知标记为已读
+func (m *GitHubMonitor) MarkAllRead(ctx context.Context) error {
+	body := map[string]interface{}{"last_read_at": time.Now().UTC().Format(time.RFC3339)}
+	if err := m.doRequest(ctx, "PUT", "/notifications", body); err != nil {
+		return fmt.Errorf("标记全部通知为已读失败: %w", err)
+	}
+	logger.Info("GitHub: 已将全部通知标记为已读")
+	return nil
+}
+
+// MarkThreadRead 将通知线程标记为已读（PATCH /notifications/threads/{id}）
+func (m *GitHubMonitor) MarkThreadRead(ctx context.Context, threadID string) error {
+	if err := m.doRequest(ctx, "PATCH", "/notifications/threads/"+url.PathEscape(threadID), nil); err != nil {
+		return fmt.Errorf("标记通知线程 %s 为已读失败: %w", threadID, err)
+	}
+	logger.Infof("GitHub: 已将通知线程 %s 标记为已读", threadID)
+	return nil
+}
+
+// MarkThreadDone 将通知线程标记为完成（DELETE /notifications/threads/{id}）
+func (m *GitHubMonitor) MarkThreadDone(ctx context.Context, threadID string) error {
+	if err := m.doRequest(ctx, "DELETE", "/notifications/threads/"+url.PathEscape(threadID), nil); err != nil {
+		return fmt.Errorf("标记通知线程 %s 为完成失败: %w", threadID, err)
+	}
+	logger.Infof("GitHub: 已将通知线程 %s 标记为完成", threadID)
+	return nil
+}
+
+// IgnoreThread 取消订阅通知线程（PUT /notifications/threads/{id}/subscription，ignored=true）
+func (m *GitHubMonitor) IgnoreThread(ctx context.Context, threadID string) error {
+	body := map[string]interface{}{"ignored": true}
+	if err := m.doRequest(ctx, "PUT", "/notifications/threads/"+url.PathEscape(threadID)+"/subscription", body); err != nil {
+		return fmt.Errorf("取消订阅通知线程 %s 失败: %w", threadID, err)
+	}
+	logger.Infof("GitHub: 已取消订阅通知线程 %s", threadID)
+	return nil
+}
+
+// MarkRepoRead 将仓库内的通知全部标记为已读（PUT /repos/{owner}/{repo}/notifications）
+func (m *GitHubMonitor) MarkRepoRead(ctx context.Context, repoFullName string) error {
+	owner, repo, ok := strings.Cut(repoFullName, "/")
+	if !ok || owner == "" || repo == "" {
+		return fmt.Errorf("无效的仓库名称: %s", repoFullName)
+	}
+
+	body := map[string]interface{}{"last_read_at": time.Now().UTC().Format(time.RFC3339)}
+	path := fmt.Sprintf("/repos/%s/%s/notifications", url.PathEscape(owner), url.PathEscape(repo))
+	if err := m.doRequest(ctx, "PUT", path, body); err != nil {
+		return fmt.Errorf("标记仓库 %s 的通知为已读失败: %w", repoFullName, err)
+	}
+	logger.Infof("GitHub: 已将仓库 %s 的通知全部标记为已读", repoFullName)
+	return nil
+}
+
+// doRequest 发送请求并丢弃响应内容，2xx 状态码视为成功
+func (m *GitHubMonitor) doRequest(ctx context.Context, method, path string, body interface{}) error {
+	if m.token == "" {
+		return fmt.Errorf("GitHub token 未设置")
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, m.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+m.token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+	m.updatePollHint(resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API 返回错误状态码 %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// Cursor 实现 Cursorer 接口，返回上次查询时间（RFC 3339），没有时返回空字符串
+func (m *GitHubMonitor) Cursor() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.lastModified.IsZero() {
+		return ""
+	}
+	return m.lastModified.UTC().Format(time.RFC3339)
+}
+
+// SetCursor 实现 Cursorer 接口，恢复上次查询时间
+func (m *GitHubMonitor) SetCursor(cursor string) error {
+	if cursor == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, cursor)
+	if err != nil {
+		return fmt.Errorf("解析查询时间失败: %w", err)
+	}
+	m.mu.Lock()
+	m.lastModified = t
+	m.mu.Unlock()
+	return nil
+}
+
+// PollHint 实现 PollHinter 接口
+func (m *GitHubMonitor) PollHint() PollHint {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pollHint
+}
+
+// SetPollHint 实现 PollHinter 接口
+func (m *GitHubMonitor) SetPollHint(hint PollHint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pollHint = hint
+}
+
+// updatePollHint 根据响应头更新轮询建议
+// X-Poll-Interval 是服务端允许的最小轮询间隔；X-RateLimit-Remaining 为 0 时暂停到 X-RateLimit-Reset；
+// 被限流（403/429）时按 Retry-After 暂停
+func (m *GitHubMonitor) updatePollHint(resp *http.Response) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if value := resp.Header.Get("X-Poll-Interval"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			m.pollHint.MinInterval = time.Duration(seconds) * time.Second
+			logger.Debugf("GitHub API 响应头 X-Poll-Interval: %d 秒", seconds)
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			resetAt := time.Unix(reset, 0)
+			if resetAt.After(m.pollHint.NotBefore) {
+				m.pollHint.NotBefore = resetAt
+				m.pollHint.Reason = fmt.Sprintf("API 配额已用完，将于 %s 重置", resetAt.Format("15:04:05"))
+				logger.Warnf("GitHub %s", m.pollHint.Reason)
+			}
+		}
+	}
+
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if retryAt := parseRetryAfter(resp.Header.Get("Retry-After")); retryAt.After(m.pollHint.NotBefore) {
+			m.pollHint.NotBefore = retryAt
+			m.pollHint.Reason = fmt.Sprintf("请求被限流，%s 之后重试", retryAt.Format("15:04:05"))
+			logger.Warnf("GitHub %s", m.pollHint.Reason)
+		}
+	}
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或 HTTP 时间），无效时返回零值
+func parseRetryAfter(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// threadIDOf 从会话标识（github_{threadID}）中解析 GitHub 通知线程 ID
+// 通知 ID 为 github_{threadID}_{更新时间}，旧版本保存的通知 ID 与会话标识相同
+func threadIDOf(notification *types.Notification) (string, error) {
+	threadID, ok := strings.CutPrefix(notification.Thread(), "github_")
+	if !ok || threadID == "" {
+		return "", fmt.Errorf("不是 GitHub 通知: %s", notification.ID)
+	}
+	return threadID, nil
+}
+
 // convertGitHubAPIToHTML 将 GitHub API URL 转换为 HTML URL
-func (m *GitHubMonitor) convertGitHubAPIToHTML(apiURL string, subjectType string, repoFullName string) string {
+func (m *GitHubMonitor) convertGitHubAPIToHTML(ctx context.Context, apiURL string, subjectType string, repoFullName string) string {
 	// GitHub API URL 格式: https://api.github.com/repos/{owner}/{repo}/issues/{number}
 	// 或 https://api.github.com/repos/{owner}/{repo}/pulls/{number}
 	// 或 https://api.github.com/repos/{owner}/{repo}/releases/{id}
@@ -220,12 +485,12 @@ func (m *GitHubMonitor) convertGitHubAPIToHTML(apiURL string, subjectType string
 	if apiURL == "" {
 		return ""
 	}
-	
+
 	// 对于 Release 类型的通知，需要特殊处理
 	if subjectType == "Release" {
-		return m.convertReleaseAPIToHTML(apiURL, repoFullName)
+		return m.convertReleaseAPIToHTML(ctx, apiURL, repoFullName)
 	}
-	
+
 	// 检查是否是 GitHub API URL
 	if len(apiURL) >= 22 && apiURL[:22] == "https://api.github.com" {
 		// 去掉 https://api.github.com 前缀
@@ -247,64 +512,64 @@ func (m *GitHubMonitor) convertGitHubAPIToHTML(apiURL string, subjectType string
 
 // convertReleaseAPIToHTML 将 Release API URL 转换为 HTML URL
 // 需要调用 API 获取 release 详情来获取 tag_name
-func (m *GitHubMonitor) convertReleaseAPIToHTML(apiURL string, repoFullName string) string {
+func (m *GitHubMonitor) convertReleaseAPIToHTML(ctx context.Context, apiURL string, repoFullName string) string {
 	if apiURL == "" {
 		return ""
 	}
-	
+
 	logger.Debugf("处理 Release 类型通知，API URL: %s, Repo: %s", apiURL, repoFullName)
-	
+
 	// 调用 API 获取 release 详情
-	req, err := http.NewRequest("GET", apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		logger.Errorf("创建 Release API 请求失败: %v", err)
 		return ""
 	}
-	
+
 	req.Header.Set("Authorization", "token "+m.token)
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
-	
+
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
 		logger.Errorf("请求 Release API 失败: %v", err)
 		return ""
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		logger.Errorf("Release API 返回错误状态码 %d: %s", resp.StatusCode, string(bodyBytes))
 		return ""
 	}
-	
+
 	var release struct {
 		TagName string `json:"tag_name"`
 		HTMLURL string `json:"html_url"`
 	}
-	
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		logger.Errorf("读取 Release API 响应失败: %v", err)
 		return ""
 	}
-	
+
 	if err := json.Unmarshal(bodyBytes, &release); err != nil {
 		logger.Errorf("解析 Release API 响应失败: %v", err)
 		return ""
 	}
-	
+
 	// 优先使用 API 返回的 html_url，如果没有则使用 tag_name 构建
 	if release.HTMLURL != "" {
 		logger.Debugf("Release API 返回 HTML URL: %s", release.HTMLURL)
 		return release.HTMLURL
 	}
-	
+
 	if release.TagName != "" {
 		htmlURL := fmt.Sprintf("https://github.com/%s/releases/tag/%s", repoFullName, release.TagName)
 		logger.Debugf("使用 tag_name 构建 Release HTML URL: %s (tag_name=%s)", htmlURL, release.TagName)
 		return htmlURL
 	}
-	
+
 	logger.Warnf("Release API 响应中未找到 tag_name 或 html_url")
 	return ""
 }
@@ -1,12 +1,14 @@
 package monitor
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -34,6 +36,12 @@ type Ld246Monitor struct {
 	messagesStateFilePath string                   // 消息状态文件路径
 }
 
+func init() {
+	Register("ld246", func(cfg *types.Config) Source {
+		return NewLd246Monitor(cfg.Ld246.Token)
+	})
+}
+
 // NewLd246Monitor 创建新的 ld246 监控器
 func NewLd246Monitor(token string) *Ld246Monitor {
 	m := &Ld246Monitor{
@@ -224,11 +232,47 @@ func (m *Ld246Monitor) saveSeenMessages() {
 	logger.Debugf("已保存 %d 个已见过的 ld246 消息 ID 到文件", len(messageIDs))
 }
 
+// Name 实现 Source 接口
+func (m *Ld246Monitor) Name() string {
+	return "ld246"
+}
+
+// Capabilities 实现 Source 接口
+// ld246 只支持按分类或全部标记已读，不能只标记单条消息，因此不实现 ReadMarker：
+// 阅读单条通知时不同步到站点，避免把同一分类的其他消息一并标记为已读
+func (m *Ld246Monitor) Capabilities() []Capability {
+	return []Capability{CapabilityMarkAllRead}
+}
+
+// Fetch 实现 Source 接口，依次获取最近回帖和未读消息
+// 其中一项失败时仍返回另一项的结果，只有两项都失败时才返回错误
+func (m *Ld246Monitor) Fetch(ctx context.Context) ([]*types.Notification, error) {
+	replies, repliesErr := m.FetchRecentReplies(ctx)
+	if repliesErr != nil {
+		logger.Errorf("获取 ld246 最近回帖失败: %v", repliesErr)
+	}
+
+	messages, messagesErr := m.FetchUnreadMessages(ctx)
+	if messagesErr != nil {
+		logger.Errorf("获取 ld246 未读消息失败: %v", messagesErr)
+	}
+
+	if repliesErr != nil && messagesErr != nil {
+		return nil, fmt.Errorf("最近回帖: %v; 未读消息: %v", repliesErr, messagesErr)
+	}
+
+	notifications := append(replies, messages...)
+	for _, n := range notifications {
+		n.Actions = ld246Actions(n.Link)
+	}
+	return notifications, nil
+}
+
 // FetchRecentReplies 获取最近回帖（按最近回帖排序的最新帖子列表）
-func (m *Ld246Monitor) FetchRecentReplies() ([]*types.Notification, error) {
+func (m *Ld246Monitor) FetchRecentReplies(ctx context.Context) ([]*types.Notification, error) {
 	url := fmt.Sprintf("%s/api/v2/articles/latest/reply?p=1", m.baseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -274,13 +318,14 @@ func (m *Ld246Monitor) FetchRecentReplies() ([]*types.Notification, error) {
 				PaginationPageNums  []int `json:"paginationPageNums"`
 			} `json:"pagination"`
 			Articles []struct {
-				OID                   string `json:"oId"`                   // 帖子 ID
-				ArticleTitle          string `json:"articleTitle"`          // 帖子标题
-				ArticlePreviewContent string `json:"articlePreviewContent"` // 帖子预览内容
-				ArticleAuthorName     string `json:"articleAuthorName"`     // 作者名称
-				ArticleCreateTime     int64  `json:"articleCreateTime"`     // 创建时间
-				ArticleUpdateTime     int64  `json:"articleUpdateTime"`     // 更新时间
-				ArticleCommentCount   int    `json:"articleCommentCount"`   // 评论数
+				OID                   string `json:"oId"`                       // 帖子 ID
+				ArticleTitle          string `json:"articleTitle"`              // 帖子标题
+				ArticlePreviewContent string `json:"articlePreviewContent"`     // 帖子预览内容
+				ArticleAuthorName     string `json:"articleAuthorName"`         // 作者名称
+				ArticleAuthorAvatar   string `json:"articleAuthorThumbnailURL"` // 作者头像 URL
+				ArticleCreateTime     int64  `json:"articleCreateTime"`         // 创建时间
+				ArticleUpdateTime     int64  `json:"articleUpdateTime"`         // 更新时间
+				ArticleCommentCount   int    `json:"articleCommentCount"`       // 评论数
 			} `json:"articles"`
 		} `json:"data"`
 		Msg string `json:"msg"`
@@ -387,12 +432,21 @@ func (m *Ld246Monitor) FetchRecentReplies() ([]*types.Notification, error) {
 		}
 
 		notification := &types.Notification{
-			ID:      notificationID,
-			Title:   title,
-			Content: content,
-			Link:    fmt.Sprintf("%s/article/%s", m.baseURL, item.OID),
-			Source:  "ld246",
-			Time:    timeValue,
+			ID:        notificationID,
+			ThreadKey: ld246ThreadKey(item.OID),
+			Title:     title,
+			Content:   content,
+			Link:      fmt.Sprintf("%s/article/%s", m.baseURL, item.OID),
+			Source:    "ld246",
+			Kind:      "article",
+			Author:    item.ArticleAuthorName,
+			AvatarURL: item.ArticleAuthorAvatar,
+			Time:      timeValue,
+			Unread:    true,
+			Extra: map[string]string{
+				"article_id":    item.OID,
+				"comment_count": strconv.Itoa(item.ArticleCommentCount),
+			},
 		}
 		newNotifications = append(newNotifications, notification)
 
@@ -452,12 +506,12 @@ func (m *Ld246Monitor) FetchRecentReplies() ([]*types.Notification, error) {
 }
 
 // FetchUnreadMessages 获取未读消息（获取收到的回帖、提及我的等消息）
-func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
+func (m *Ld246Monitor) FetchUnreadMessages(ctx context.Context) ([]*types.Notification, error) {
 	logger.Debug("开始获取 ld246 未读消息...")
 
 	// 获取未读消息计数
 	countURL := fmt.Sprintf("%s/api/v2/notifications/unread/count", m.baseURL)
-	req, err := http.NewRequest("GET", countURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", countURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -550,7 +604,7 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 	// 获取收到的回帖消息
 	if countResp.Data.UnreadCommentedNotificationCnt > 0 {
 		logger.Debug("开始获取 ld246 收到的回帖消息...")
-		commentedNotifications, err := m.fetchNotificationsByType("commented")
+		commentedNotifications, err := m.fetchNotificationsByType(ctx, "commented")
 		if err != nil {
 			logger.Errorf("获取收到的回帖消息失败: %v", err)
 		} else {
@@ -562,7 +616,7 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 	// 获取提及我的消息
 	if countResp.Data.UnreadAtNotificationCnt > 0 {
 		logger.Debug("开始获取 ld246 提及我的消息...")
-		atNotifications, err := m.fetchNotificationsByType("at")
+		atNotifications, err := m.fetchNotificationsByType(ctx, "at")
 		if err != nil {
 			logger.Errorf("获取提及我的消息失败: %v", err)
 		} else {
@@ -574,7 +628,7 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 	// 获取收到的回复消息
 	if countResp.Data.UnreadReplyNotificationCnt > 0 {
 		logger.Debug("开始获取 ld246 收到的回复消息...")
-		replyNotifications, err := m.fetchNotificationsByType("reply")
+		replyNotifications, err := m.fetchNotificationsByType(ctx, "reply")
 		if err != nil {
 			logger.Errorf("获取收到的回复消息失败: %v", err)
 		} else {
@@ -586,7 +640,7 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 	// 获取我关注的消息
 	if countResp.Data.UnreadFollowingNotificationCnt > 0 {
 		logger.Debug("开始获取 ld246 我关注的消息...")
-		followingNotifications, err := m.fetchNotificationsByType("following")
+		followingNotifications, err := m.fetchNotificationsByType(ctx, "following")
 		if err != nil {
 			logger.Errorf("获取我关注的消息失败: %v", err)
 		} else {
@@ -598,7 +652,7 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 	// 获取收到的评论消息（comment2ed API 的数据结构与其他通知类型不同，需要单独处理）
 	if countResp.Data.UnreadComment2edNotificationCnt > 0 {
 		logger.Infof("开始获取 ld246 收到的评论消息（未读数量: %d）...", countResp.Data.UnreadComment2edNotificationCnt)
-		comment2edNotifications, err := m.fetchComment2edNotifications()
+		comment2edNotifications, err := m.fetchComment2edNotifications(ctx)
 		if err != nil {
 			logger.Errorf("获取收到的评论消息失败: %v", err)
 		} else {
@@ -617,12 +671,16 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 
 		// 只要数量 > 0，就生成通知
 		chatNotification := &types.Notification{
-			ID:      fmt.Sprintf("ld246_chat_%d", currentCount),
-			Title:   fmt.Sprintf("聊天消息 (%d)", currentCount),
-			Content: fmt.Sprintf("您有 %d 条未读聊天消息", currentCount),
-			Link:    "https://ld246.com/chats",
-			Source:  "ld246",
-			Time:    time.Now().UnixMilli(),
+			ID:        fmt.Sprintf("ld246_chat_%d", currentCount),
+			ThreadKey: "ld246_chat", // 未读聊天数变化时替换之前的提醒
+			Title:     fmt.Sprintf("聊天消息 (%d)", currentCount),
+			Content:   fmt.Sprintf("您有 %d 条未读聊天消息", currentCount),
+			Link:      "https://ld246.com/chats",
+			Source:    "ld246",
+			Kind:      "chat",
+			Time:      time.Now().UnixMilli(),
+			Unread:    true,
+			Extra:     map[string]string{"unread_count": strconv.Itoa(currentCount)},
 		}
 		notifications = append(notifications, chatNotification)
 
@@ -642,10 +700,10 @@ func (m *Ld246Monitor) FetchUnreadMessages() ([]*types.Notification, error) {
 }
 
 // fetchNotificationsByType 根据类型获取通知消息
-func (m *Ld246Monitor) fetchNotificationsByType(notificationType string) ([]*types.Notification, error) {
+func (m *Ld246Monitor) fetchNotificationsByType(ctx context.Context, notificationType string) ([]*types.Notification, error) {
 	url := fmt.Sprintf("%s/api/v2/notifications/%s?p=1", m.baseURL, notificationType)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -686,12 +744,14 @@ func (m *Ld246Monitor) fetchNotificationsByType(notificationType string) ([]*typ
 	var apiResp struct {
 		Code int `json:"code"`
 		Data []struct {
-			ID          string `json:"id"`
-			Msg         string `json:"msg"`         // 消息内容
-			DataType    int    `json:"dataType"`    // 数据类型
-			DataID      string `json:"dataId"`      // 关联数据 ID
-			CreatedTime int64  `json:"createdTime"` // 创建时间
-			HasRead     bool   `json:"hasRead"`     // 是否已读
+			ID              string `json:"id"`
+			Msg             string `json:"msg"`             // 消息内容
+			DataType        int    `json:"dataType"`        // 数据类型
+			DataID          string `json:"dataId"`          // 关联数据 ID
+			AuthorName      string `json:"authorName"`      // 作者名称
+			AuthorAvatarURL string `json:"authorAvatarURL"` // 作者头像 URL
+			CreatedTime     int64  `json:"createdTime"`     // 创建时间
+			HasRead         bool   `json:"hasRead"`         // 是否已读
 		} `json:"data"`
 		Msg string `json:"msg"`
 	}
@@ -773,12 +833,18 @@ func (m *Ld246Monitor) fetchNotificationsByType(notificationType string) ([]*typ
 		}
 
 		notification := &types.Notification{
-			ID:      fmt.Sprintf("ld246_%s_%s", notificationType, item.ID),
-			Title:   title,
-			Content: content,
-			Link:    link,
-			Source:  "ld246",
-			Time:    item.CreatedTime,
+			ID:        fmt.Sprintf("ld246_%s_%s", notificationType, item.ID),
+			ThreadKey: ld246ThreadKey(item.DataID),
+			Title:     title,
+			Content:   content,
+			Link:      link,
+			Source:    "ld246",
+			Kind:      notificationType,
+			Author:    item.AuthorName,
+			AvatarURL: item.AuthorAvatarURL,
+			Time:      item.CreatedTime,
+			Unread:    !item.HasRead,
+			Extra:     ld246Extra(item.DataID, item.DataType),
 		}
 		newNotifications = append(newNotifications, notification)
 		newMessageIDs = append(newMessageIDs, messageID)
@@ -812,10 +878,10 @@ func (m *Ld246Monitor) fetchNotificationsByType(notificationType string) ([]*typ
 }
 
 // fetchComment2edNotifications 获取收到的评论消息（comment2ed API 的数据结构与其他通知类型不同）
-func (m *Ld246Monitor) fetchComment2edNotifications() ([]*types.Notification, error) {
+func (m *Ld246Monitor) fetchComment2edNotifications(ctx context.Context) ([]*types.Notification, error) {
 	url := fmt.Sprintf("%s/api/v2/notifications/comment2ed?p=1", m.baseURL)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("创建请求失败: %w", err)
 	}
@@ -943,12 +1009,18 @@ func (m *Ld246Monitor) fetchComment2edNotifications() ([]*types.Notification, er
 		}
 
 		notification := &types.Notification{
-			ID:      fmt.Sprintf("ld246_comment2ed_%s", messageID),
-			Title:   title,
-			Content: content,
-			Link:    link,
-			Source:  "ld246",
-			Time:    time.Now().UnixMilli(), // comment2ed 类型没有 createdTime 字段，使用当前时间
+			ID:        fmt.Sprintf("ld246_comment2ed_%s", messageID),
+			ThreadKey: ld246ThreadKey(item.DataID),
+			Title:     title,
+			Content:   content,
+			Link:      link,
+			Source:    "ld246",
+			Kind:      "comment2ed",
+			Author:    item.AuthorName,
+			AvatarURL: item.AuthorAvatarURL,
+			Time:      time.Now().UnixMilli(), // comment2ed 类型没有 createdTime 字段，使用当前时间
+			Unread:    !item.HasRead,
+			Extra:     ld246Extra(item.DataID, item.DataType),
 		}
 		newNotifications = append(newNotifications, notification)
 		newMessageIDs = append(newMessageIDs, messageID)
@@ -981,6 +1053,124 @@ func (m *Ld246Monitor) fetchComment2edNotifications() ([]*types.Notification, er
 	return newNotifications, nil
 }
 
+// Ld246Categories 支持标记已读的 ld246 消息分类
+var Ld246Categories = []string{"commented", "at", "reply", "comment2ed", "following"}
+
+// Test 实现 Tester 接口，请求未读消息计数检查 token 是否有效
+func (m *Ld246Monitor) Test(ctx context.Context) error {
+	if err := m.doGet(ctx, "/api/v2/notifications/unread/count"); err != nil {
+		return fmt.Errorf("检查 ld246 连接失败: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead 实现 AllReadMarker 接口，将所有消息标记为已读
+func (m *Ld246Monitor) MarkAllRead(ctx context.Context) error {
+	if err := m.doGet(ctx, "/api/v2/notifications/all-read"); err != nil {
+		return fmt.Errorf("标记全部消息为已读失败: %w", err)
+	}
+	logger.Info("ld246: 已将全部消息标记为已读")
+	return nil
+}
+
+// MarkCategoryRead 将指定分类的消息全部标记为已读
+func (m *Ld246Monitor) MarkCategoryRead(ctx context.Context, category string) error {
+	if !isLd246Category(category) {
+		return fmt.Errorf("不支持的消息分类: %s", category)
+	}
+	if err := m.doGet(ctx, "/api/v2/notifications/make-read/"+category); err != nil {
+		return fmt.Errorf("标记 %s 消息为已读失败: %w", category, err)
+	}
+	logger.Infof("ld246: 已将 %s 消息标记为已读", category)
+	return nil
+}
+
+// doGet 调用无返回数据的 GET 接口，检查状态码和业务返回码
+func (m *Ld246Monitor) doGet(ctx context.Context, path string) error {
+	if m.token == "" {
+		return fmt.Errorf("ld246 token 未设置")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", m.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+m.token)
+	req.Header.Set("User-Agent", "NotifyMe/1.0")
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("需要登录，请设置有效的 API Token")
+	}
+	if resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("权限不足")
+	}
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应体失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API 返回错误状态码 %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var apiResp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	if apiResp.Code != 0 {
+		return fmt.Errorf("API 返回错误: %s", apiResp.Msg)
+	}
+	return nil
+}
+
+// Ld246CategoryOf 从通知 ID（ld246_{分类}_...）中解析消息分类，不属于消息分类时返回空字符串
+func Ld246CategoryOf(notification *types.Notification) string {
+	rest, ok := strings.CutPrefix(notification.ID, "ld246_")
+	if !ok {
+		return ""
+	}
+	category, _, _ := strings.Cut(rest, "_")
+	if !isLd246Category(category) {
+		return ""
+	}
+	return category
+}
+
+// ld246ThreadKey 根据帖子 ID 生成会话标识，与链接一样以 dataId 作为帖子 ID
+// 没有关联数据时返回空字符串，即每条消息单独成组
+func ld246ThreadKey(articleID string) string {
+	if articleID == "" {
+		return ""
+	}
+	return "ld246_article_" + articleID
+}
+
+// ld246Extra 消息通知的附加信息（关联数据 ID 和数据类型）
+func ld246Extra(dataID string, dataType int) map[string]string {
+	return map[string]string{
+		"data_id":   dataID,
+		"data_type": strconv.Itoa(dataType),
+	}
+}
+
+// isLd246Category 检查是否为支持标记已读的消息分类
+func isLd246Category(category string) bool {
+	for _, c := range Ld246Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
 // truncateString 截断字符串到指定长度
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
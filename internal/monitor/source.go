@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"notifyme/pkg/types"
+)
+
+// Capability 数据源支持的能力
+type Capability string
+
+const (
+	// CapabilityIncremental 支持增量拉取（只返回上次拉取之后的新通知）
+	CapabilityIncremental Capability = "incremental"
+	// CapabilityMarkRead 支持把单条通知在站点上标记为已读（实现 ReadMarker）
+	CapabilityMarkRead Capability = "mark_read"
+	// CapabilityMarkAllRead 支持把站点上的通知全部标记为已读（实现 AllReadMarker）
+	CapabilityMarkAllRead Capability = "mark_all_read"
+	// CapabilityMarkDone 支持把单条通知在站点上标记为完成（实现 DoneMarker）
+	CapabilityMarkDone Capability = "mark_done"
+	// CapabilityMuteThread 支持在站点上取消订阅通知所在的会话（实现 ThreadMuter）
+	CapabilityMuteThread Capability = "mute_thread"
+)
+
+// Source 通知数据源
+// 每个数据源负责从一个站点拉取新通知，调度器会为每个数据源启动独立的轮询循环
+type Source interface {
+	// Name 数据源名称，同时作为通知的 Source 字段和配置中的键
+	Name() string
+	// Fetch 拉取新通知，ctx 取消时应尽快返回
+	Fetch(ctx context.Context) ([]*types.Notification, error)
+	// Capabilities 数据源支持的能力列表
+	Capabilities() []Capability
+}
+
+// ReadMarker 支持把通知在站点上标记为已读的数据源
+type ReadMarker interface {
+	MarkRead(ctx context.Context, notification *types.Notification) error
+}
+
+// AllReadMarker 支持把站点上的通知全部标记为已读的数据源
+type AllReadMarker interface {
+	MarkAllRead(ctx context.Context) error
+}
+
+// DoneMarker 支持把通知在站点上标记为完成（从站点收件箱移除）的数据源
+type DoneMarker interface {
+	MarkDone(ctx context.Context, notification *types.Notification) error
+}
+
+// ThreadMuter 支持在站点上取消订阅通知所在会话的数据源，取消后站点不再产生该会话的通知
+type ThreadMuter interface {
+	MuteThread(ctx context.Context, notification *types.Notification) error
+}
+
+// Tester 支持检查连接和凭据是否有效的数据源
+// 检查只发送一次轻量请求，不拉取通知，也不改变增量拉取位置和已见过的记录
+type Tester interface {
+	Test(ctx context.Context) error
+}
+
+// PollHint 数据源根据服务端响应给出的轮询建议
+type PollHint struct {
+	MinInterval time.Duration // 服务端要求的最小轮询间隔，0 表示没有要求
+	NotBefore   time.Time     // 在此时间之前不要再请求（如配额耗尽、Retry-After），零值表示不限制
+	Reason      string        // NotBefore 的原因
+}
+
+// PollHinter 能给出轮询建议的数据源，调度器每次检查后会据此调整下一次轮询时间
+// 修改配置重建数据源时，调度器通过 SetPollHint 把旧实例的轮询建议交给新实例，避免配额耗尽或限流期间立即重新请求
+type PollHinter interface {
+	PollHint() PollHint
+	SetPollHint(hint PollHint)
+}
+
+// Cursorer 支持保存和恢复增量拉取位置的数据源
+// 调度器在每次拉取成功后持久化 Cursor，重启或重建数据源后通过 SetCursor 恢复，避免重复拉取
+type Cursorer interface {
+	Cursor() string
+	SetCursor(cursor string) error
+}
+
+// Factory 根据配置创建数据源
+type Factory func(cfg *types.Config) Source
+
+var (
+	registryMu sync.RWMutex
+	factories  = make(map[string]Factory)
+	names      []string // 按注册顺序保存的数据源名称
+)
+
+// Register 注册数据源工厂
+// 一般在数据源实现文件的 init 中调用，重复注册同名数据源会 panic
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("monitor: Register factory is nil")
+	}
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("monitor: 数据源 %s 重复注册", name))
+	}
+	factories[name] = factory
+	names = append(names, name)
+}
+
+// Registered 返回已注册的数据源名称（按注册顺序）
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	result := make([]string, len(names))
+	copy(result, names)
+	return result
+}
+
+// NewSources 根据配置创建所有已注册的数据源（按注册顺序）
+// 工厂返回 nil 表示该数据源在当前配置下不可用，会被跳过
+func NewSources(cfg *types.Config) []Source {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	sources := make([]Source, 0, len(names))
+	for _, name := range names {
+		if src := factories[name](cfg); src != nil {
+			sources = append(sources, src)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,3 @@
+// Package desktop 注册桌面通知渠道（Windows Toast、Linux D-Bus）
+// 依赖图形界面相关的系统库，只由桌面版程序导入；无界面的守护进程不导入该包，desktop 渠道不可用
+package desktop
@@ -0,0 +1,227 @@
+//go:build linux
+
+package desktop
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	"notifyme/pkg/types"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusNotificationsName  = "org.freedesktop.Notifications"
+	dbusNotificationsPath  = dbus.ObjectPath("/org/freedesktop/Notifications")
+	dbusNotificationsIface = "org.freedesktop.Notifications"
+)
+
+// 通知紧急程度（freedesktop 规范中的 urgency hint）
+var urgencyLevels = map[string]byte{
+	"low":      0,
+	"normal":   1,
+	"critical": 2,
+}
+
+func init() {
+	notifier.RegisterSink("desktop", func(cfg types.SinkConfig) (notifier.Sink, error) {
+		conn, err := dbus.ConnectSessionBus()
+		if err != nil {
+			return nil, fmt.Errorf("连接 D-Bus 会话总线失败: %w", err)
+		}
+		n, err := NewLinuxNotifier(conn, cfg.Urgency)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return n, nil
+	})
+}
+
+// LinuxNotifier Linux 桌面通知器，通过 D-Bus 调用 org.freedesktop.Notifications 服务
+// 是否重复投递由调度器的投递记录决定，通知器本身不去重
+type LinuxNotifier struct {
+	conn       *dbus.Conn
+	obj        dbus.BusObject
+	urgency    byte
+	signals    chan *dbus.Signal
+	replaceIDs map[string]uint32              // 会话标识 -> 服务端通知 ID，用于原位替换
+	active     map[uint32]*types.Notification // 服务端通知 ID -> 通知，用于处理动作
+	mu         sync.Mutex
+}
+
+// NewLinuxNotifier 使用指定的 D-Bus 连接创建 Linux 通知器
+// 连接的生命周期交由通知器管理，Close 时一并关闭；
+// 测试时可以传入 dbus.Connect 连接到私有会话总线
+func NewLinuxNotifier(conn *dbus.Conn, urgency string) (*LinuxNotifier, error) {
+	level, ok := urgencyLevels[urgency]
+	if !ok {
+		level = urgencyLevels["normal"]
+	}
+
+	n := &LinuxNotifier{
+		conn:       conn,
+		obj:        conn.Object(dbusNotificationsName, dbusNotificationsPath),
+		urgency:    level,
+		signals:    make(chan *dbus.Signal, 16),
+		replaceIDs: make(map[string]uint32),
+		active:     make(map[uint32]*types.Notification),
+	}
+
+	// 订阅动作和关闭信号
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbusNotificationsPath),
+		dbus.WithMatchInterface(dbusNotificationsIface),
+	); err != nil {
+		return nil, fmt.Errorf("订阅通知信号失败: %w", err)
+	}
+	conn.Signal(n.signals)
+	go n.handleSignals()
+
+	return n, nil
+}
+
+// Notify 发送通知
+// 同一会话再次投递（如 GitHub 线程或 ld246 帖子有更新）时会替换之前弹出的通知，而不是叠加
+func (n *LinuxNotifier) Notify(notification *types.Notification) error {
+	n.mu.Lock()
+	replaceID := n.replaceIDs[notification.Thread()]
+	n.mu.Unlock()
+
+	message := notification.Content
+	if message == "" {
+		message = "点击查看详情"
+	}
+
+	actions := dbusActions(notification)
+	// 规则设置了优先级时覆盖渠道配置的紧急程度
+	urgency := n.urgency
+	switch notification.Priority {
+	case "low":
+		urgency = urgencyLevels["low"]
+	case "high":
+		urgency = urgencyLevels["critical"]
+	}
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(urgency),
+		"desktop-entry": dbus.MakeVariant("notifyme"),
+	}
+
+	var id uint32
+	call := n.obj.Call(dbusNotificationsIface+".Notify", 0,
+		"NotifyMe",         // app_name
+		replaceID,          // replaces_id
+		"",                 // app_icon
+		notification.Title, // summary
+		message,            // body
+		actions,            // actions
+		hints,              // hints
+		int32(-1),          // expire_timeout，-1 表示由服务端决定
+	)
+	if err := call.Store(&id); err != nil {
+		return fmt.Errorf("发送通知失败: %w", err)
+	}
+
+	n.mu.Lock()
+	if replaceID != 0 && replaceID != id {
+		delete(n.active, replaceID)
+	}
+	n.replaceIDs[notification.Thread()] = id
+	n.active[id] = notification
+	n.mu.Unlock()
+
+	logger.Infof("已发送通知: %s - %s", notification.Title, notification.ID)
+	return nil
+}
+
+// Close 关闭通知器并断开 D-Bus 连接
+func (n *LinuxNotifier) Close() error {
+	n.conn.RemoveSignal(n.signals)
+	close(n.signals)
+	return n.conn.Close()
+}
+
+// handleSignals 处理通知服务发出的信号
+func (n *LinuxNotifier) handleSignals() {
+	for signal := range n.signals {
+		switch signal.Name {
+		case dbusNotificationsIface + ".ActionInvoked":
+			var id uint32
+			var action string
+			if err := dbus.Store(signal.Body, &id, &action); err != nil {
+				logger.Warnf("解析通知动作信号失败: %v", err)
+				continue
+			}
+			n.handleAction(id, action)
+		case dbusNotificationsIface + ".NotificationClosed":
+			var id, reason uint32
+			if err := dbus.Store(signal.Body, &id, &reason); err != nil {
+				continue
+			}
+			n.mu.Lock()
+			if notification, ok := n.active[id]; ok {
+				delete(n.active, id)
+				if n.replaceIDs[notification.Thread()] == id {
+					delete(n.replaceIDs, notification.Thread())
+				}
+			}
+			n.mu.Unlock()
+		}
+	}
+}
+
+// handleAction 处理用户点击的通知动作
+func (n *LinuxNotifier) handleAction(id uint32, action string) {
+	n.mu.Lock()
+	notification, ok := n.active[id]
+	n.mu.Unlock()
+	if !ok {
+		// 其他应用的通知，忽略
+		return
+	}
+
+	logger.Infof("收到通知动作: %s - %s", action, notification.ID)
+
+	// 汇总通知没有对应的通知记录，点击后打开主界面
+	if notification.Link == notifier.AppLink {
+		notifier.DispatchAction(notification.ID, notifier.ActionOpenApp)
+		return
+	}
+	if action == "default" {
+		action = types.ActionOpen
+	}
+
+	url, err := notifier.DispatchAction(notification.ID, action)
+	if err != nil {
+		logger.Errorf("执行通知动作 %s 失败: %v", action, err)
+		return
+	}
+	if url == "" {
+		return
+	}
+	if err := exec.Command("xdg-open", url).Start(); err != nil {
+		logger.Errorf("打开链接失败: %v", err)
+	}
+}
+
+// dbusActions 把通知上的动作转换为 D-Bus 动作列表，格式为 [key1, label1, key2, label2, ...]
+// default 对应点击通知本身，等同于打开；汇总通知只能打开主界面
+func dbusActions(notification *types.Notification) []string {
+	actions := []string{"default", "打开"}
+	if notification.Link == notifier.AppLink {
+		return append(actions, types.ActionOpen, "打开")
+	}
+
+	if len(notification.Actions) == 0 {
+		// 旧版本保存的通知没有动作列表
+		return append(actions, types.ActionOpen, "打开", types.ActionMarkRead, "标记已读")
+	}
+	for _, action := range notification.Actions {
+		actions = append(actions, action.ID, action.Label)
+	}
+	return actions
+}
@@ -0,0 +1,253 @@
+//go:build linux
+
+package desktop
+
+import (
+	"bufio"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notifyme/internal/notifier"
+	"notifyme/pkg/types"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// notifyCall 假通知服务收到的一次 Notify 调用
+type notifyCall struct {
+	AppName   string
+	ReplaceID uint32
+	Summary   string
+	Body      string
+	Actions   []string
+	Hints     map[string]dbus.Variant
+}
+
+// fakeNotifications 在私有会话总线上导出的假 org.freedesktop.Notifications 服务
+type fakeNotifications struct {
+	mu     sync.Mutex
+	calls  []notifyCall
+	nextID uint32
+}
+
+func (f *fakeNotifications) Notify(appName string, replaceID uint32, appIcon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, notifyCall{
+		AppName:   appName,
+		ReplaceID: replaceID,
+		Summary:   summary,
+		Body:      body,
+		Actions:   actions,
+		Hints:     hints,
+	})
+	// 与真实服务一致：替换时沿用原 ID
+	if replaceID != 0 {
+		return replaceID, nil
+	}
+	f.nextID++
+	return f.nextID, nil
+}
+
+func (f *fakeNotifications) lastCall(t *testing.T) notifyCall {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.calls) == 0 {
+		t.Fatal("通知服务没有收到 Notify 调用")
+	}
+	return f.calls[len(f.calls)-1]
+}
+
+// startSessionBus 启动私有会话总线，返回总线地址；没有 dbus-daemon 时跳过测试
+func startSessionBus(t *testing.T) string {
+	t.Helper()
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("没有 dbus-daemon，跳过")
+	}
+
+	cmd := exec.Command(path, "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("启动 dbus-daemon 失败: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("读取会话总线地址失败: %v", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+// connect 连接私有会话总线并完成认证
+func connect(t *testing.T, address string) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatalf("连接会话总线失败: %v", err)
+	}
+	return conn
+}
+
+// newTestNotifier 启动私有会话总线、假通知服务和连接到它的 LinuxNotifier
+func newTestNotifier(t *testing.T) (*LinuxNotifier, *fakeNotifications, *dbus.Conn) {
+	t.Helper()
+	address := startSessionBus(t)
+
+	server := connect(t, address)
+	t.Cleanup(func() { server.Close() })
+	fake := &fakeNotifications{}
+	if err := server.Export(fake, dbusNotificationsPath, dbusNotificationsIface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := server.RequestName(dbusNotificationsName, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("注册通知服务名称失败: %v (%v)", err, reply)
+	}
+
+	n, err := NewLinuxNotifier(connect(t, address), "normal")
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	t.Cleanup(func() { n.Close() })
+	return n, fake, server
+}
+
+// waitFor 等待条件成立，超时则测试失败
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待%s超时", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLinuxNotifierNotify(t *testing.T) {
+	n, fake, _ := newTestNotifier(t)
+
+	first := &types.Notification{ID: "github_1_1", ThreadKey: "github_1", Title: "标题", Content: "内容"}
+	if err := n.Notify(first); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+	call := fake.lastCall(t)
+	if call.AppName != "NotifyMe" {
+		t.Errorf("app_name = %q, want NotifyMe", call.AppName)
+	}
+	if call.ReplaceID != 0 {
+		t.Errorf("首次通知 replaces_id = %d, want 0", call.ReplaceID)
+	}
+	if call.Summary != "标题" || call.Body != "内容" {
+		t.Errorf("summary/body = %q/%q", call.Summary, call.Body)
+	}
+	wantActions := []string{"default", "打开", types.ActionOpen, "打开", types.ActionMarkRead, "标记已读"}
+	if !reflect.DeepEqual(call.Actions, wantActions) {
+		t.Errorf("actions = %v, want %v", call.Actions, wantActions)
+	}
+	if urgency, ok := call.Hints["urgency"].Value().(byte); !ok || urgency != urgencyLevels["normal"] {
+		t.Errorf("urgency hint = %v, want %d", call.Hints["urgency"], urgencyLevels["normal"])
+	}
+
+	// 同一会话的更新替换之前的通知
+	update := &types.Notification{ID: "github_1_2", ThreadKey: "github_1", Title: "更新", Priority: "high"}
+	if err := n.Notify(update); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+	call = fake.lastCall(t)
+	if call.ReplaceID != 1 {
+		t.Errorf("同一会话 replaces_id = %d, want 1", call.ReplaceID)
+	}
+	if urgency := call.Hints["urgency"].Value().(byte); urgency != urgencyLevels["critical"] {
+		t.Errorf("高优先级 urgency = %d, want %d", urgency, urgencyLevels["critical"])
+	}
+
+	// 其他会话不替换
+	other := &types.Notification{ID: "ld246_1", ThreadKey: "ld246_article_1", Title: "其他"}
+	if err := n.Notify(other); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+	if call = fake.lastCall(t); call.ReplaceID != 0 {
+		t.Errorf("其他会话 replaces_id = %d, want 0", call.ReplaceID)
+	}
+
+	// 通知自带的动作列表
+	withActions := &types.Notification{ID: "github_2_1", ThreadKey: "github_2", Title: "动作", Actions: []types.NotificationAction{
+		{ID: types.ActionOpen, Label: "打开"},
+		{ID: types.ActionMarkDone, Label: "完成"},
+	}}
+	if err := n.Notify(withActions); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+	wantActions = []string{"default", "打开", types.ActionOpen, "打开", types.ActionMarkDone, "完成"}
+	if call = fake.lastCall(t); !reflect.DeepEqual(call.Actions, wantActions) {
+		t.Errorf("actions = %v, want %v", call.Actions, wantActions)
+	}
+}
+
+func TestLinuxNotifierSignals(t *testing.T) {
+	n, _, server := newTestNotifier(t)
+
+	type invoked struct{ id, action string }
+	actions := make(chan invoked, 1)
+	notifier.SetActionHandler(func(id, action string) (string, error) {
+		actions <- invoked{id, action}
+		return "", nil
+	})
+	t.Cleanup(func() { notifier.SetActionHandler(nil) })
+
+	notification := &types.Notification{ID: "github_1_1", ThreadKey: "github_1", Title: "标题"}
+	if err := n.Notify(notification); err != nil {
+		t.Fatalf("Notify 失败: %v", err)
+	}
+
+	// ActionInvoked 交给动作回调
+	if err := server.Emit(dbusNotificationsPath, dbusNotificationsIface+".ActionInvoked", uint32(1), types.ActionMarkRead); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-actions:
+		if got != (invoked{"github_1_1", types.ActionMarkRead}) {
+			t.Errorf("动作回调收到 %v", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("没有收到动作回调")
+	}
+
+	// 其他应用的通知动作被忽略
+	if err := server.Emit(dbusNotificationsPath, dbusNotificationsIface+".ActionInvoked", uint32(99), types.ActionMarkRead); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-actions:
+		t.Errorf("其他应用的通知不应触发回调: %v", got)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// NotificationClosed 后不再记录该通知，同一会话的下一次通知不再替换
+	if err := server.Emit(dbusNotificationsPath, dbusNotificationsIface+".NotificationClosed", uint32(1), uint32(2)); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "通知关闭", func() bool {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		_, active := n.active[1]
+		_, replace := n.replaceIDs["github_1"]
+		return !active && !replace
+	})
+}
@@ -0,0 +1,154 @@
+//go:build windows
+
+package desktop
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"notifyme/internal/assets"
+	"notifyme/internal/deeplink"
+	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	"notifyme/pkg/types"
+)
+
+// maxToastActions Windows 通知最多显示的按钮数
+const maxToastActions = 5
+
+func init() {
+	notifier.RegisterSink("desktop", func(cfg types.SinkConfig) (notifier.Sink, error) {
+		return NewWindowsNotifier(), nil
+	})
+}
+
+// WindowsNotifier Windows 系统通知器
+// 是否重复投递由调度器的投递记录决定，通知器本身不去重（稍后提醒等场景需要再次弹出同一条通知）
+type WindowsNotifier struct {
+	iconPath string // 图标文件路径
+}
+
+// NewWindowsNotifier 创建新的 Windows 通知器
+func NewWindowsNotifier() *WindowsNotifier {
+	n := &WindowsNotifier{}
+
+	// 初始化图标路径
+	n.initIcon()
+
+	// 清理上次运行中断时遗留的通知脚本
+	removeStaleToastScripts()
+
+	return n
+}
+
+// initIcon 初始化通知图标
+func (n *WindowsNotifier) initIcon() {
+	// 使用与托盘相同的应用图标
+	iconData := assets.Icon
+
+	// 如果图标数据为空，跳过
+	if len(iconData) == 0 {
+		logger.Warn("通知图标数据为空，将使用默认图标")
+		return
+	}
+
+	// 获取临时目录
+	tempDir := os.TempDir()
+	iconPath := filepath.Join(tempDir, "notifyme_icon.ico")
+
+	// 如果图标文件已存在，直接使用
+	if _, err := os.Stat(iconPath); err == nil {
+		n.iconPath = iconPath
+		return
+	}
+
+	// 写入图标文件
+	if err := os.WriteFile(iconPath, iconData, 0644); err != nil {
+		logger.Warnf("写入通知图标文件失败: %v，将使用默认图标", err)
+		return
+	}
+
+	n.iconPath = iconPath
+	logger.Debugf("通知图标已初始化: %s", iconPath)
+}
+
+// Notify 发送通知
+// 同一会话再次投递（如 GitHub 线程或 ld246 帖子有更新）时会替换操作中心中之前的通知，而不是叠加
+func (n *WindowsNotifier) Notify(notification *types.Notification) error {
+	// 构建通知内容
+	title := notification.Title
+	message := notification.Content
+	if message == "" {
+		message = "点击查看详情"
+	}
+
+	// 创建通知
+	// 尝试使用图标文件路径作为 AppID，这样 Windows 可以直接从图标文件显示图标
+	appID := "NotifyMe"
+	if n.iconPath != "" {
+		// 使用图标文件路径作为 AppID，Windows 会从图标文件中提取图标显示在标题区域
+		absIconPath, err := filepath.Abs(n.iconPath)
+		if err == nil {
+			appID = absIconPath
+		}
+	}
+
+	// 按钮和点击通知都通过 notifyme:// 链接启动程序，由单实例转发给正在运行的实例执行
+	// 链接带有本进程的令牌，正在运行的实例执行时不需要用户再次确认
+	// 汇总通知没有对应的通知记录，只能打开主界面
+	actions := []toastAction{
+		{Label: "打开", Arguments: notifier.AppLink},
+	}
+	activation := notifier.AppLink
+	if notification.Link != notifier.AppLink {
+		activation = deeplink.Sign(deeplink.Open(notification.ID))
+		actions = toastActions(notification)
+	}
+
+	// 注意：不设置图标，避免在内容区域显示图标
+	notificationToast := toastNotification{
+		AppID:               appID,
+		Title:               title,
+		Message:             message,
+		Actions:             actions,
+		ActivationArguments: activation,
+		Long:                notification.Priority == "high", // 高优先级的通知停留更长时间
+		Tag:                 toastTag(notification.Thread()),
+		Group:               notification.Source,
+	}
+
+	// 发送通知
+	if err := notificationToast.Push(); err != nil {
+		return fmt.Errorf("发送通知失败: %w", err)
+	}
+
+	logger.Infof("已发送通知: %s - %s", notification.Title, notification.ID)
+	return nil
+}
+
+// toastActions 把通知上的动作转换为通知按钮，最多 maxToastActions 个
+func toastActions(notification *types.Notification) []toastAction {
+	notificationActions := notification.Actions
+	if len(notificationActions) == 0 {
+		// 旧版本保存的通知没有动作列表
+		notificationActions = []types.NotificationAction{
+			{ID: types.ActionOpen, Label: "打开"},
+			{ID: types.ActionMarkRead, Label: "标记已读"},
+			{ID: types.ActionSnooze, Label: "1 小时后提醒"},
+		}
+	}
+
+	actions := make([]toastAction, 0, maxToastActions)
+	for _, action := range notificationActions {
+		link := deeplink.ForAction(notification.ID, action.ID)
+		if link == "" {
+			continue
+		}
+		actions = append(actions, toastAction{Label: action.Label, Arguments: deeplink.Sign(link)})
+		if len(actions) == maxToastActions {
+			break
+		}
+	}
+	return actions
+}
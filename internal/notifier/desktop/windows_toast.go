@@ -0,0 +1,157 @@
+//go:build windows
+
+package desktop
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	"notifyme/internal/logger"
+)
+
+const (
+	maxToastTagLength  = 64               // Toast 的 Tag 和 Group 的最大长度
+	maxToastProcesses  = 2                // 同时运行的 PowerShell 进程数上限，短时间内大量通知时排队显示
+	toastScriptTimeout = 30 * time.Second // 单个通知脚本的运行时间上限
+	toastScriptPattern = "notifyme-toast-*.ps1"
+)
+
+// toastSlots 限制同时运行的 PowerShell 进程数
+var toastSlots = make(chan struct{}, maxToastProcesses)
+
+// toastAction 通知上的按钮，点击后打开 Arguments 中的链接
+type toastAction struct {
+	Label     string
+	Arguments string
+}
+
+// toastNotification 一条 Windows Toast 通知
+// go-toast 不支持设置 Tag/Group，同一会话的更新只能叠加显示，因此自行生成 PowerShell 脚本调用 WinRT 接口：
+// Tag 和 Group 相同的通知会替换操作中心中之前的通知
+type toastNotification struct {
+	AppID               string
+	Title               string
+	Message             string
+	Actions             []toastAction
+	ActivationArguments string // 点击通知本身时打开的链接
+	Long                bool   // 是否长时间停留
+	Tag                 string // 同一会话使用相同的 Tag
+	Group               string
+}
+
+// toastScript 显示通知的 PowerShell 脚本
+// XML 放在单引号 here-string 中，避免 PowerShell 展开标题中的 $ 等字符；字符串参数中的单引号需要写两次
+var toastScript = template.Must(template.New("toast").Funcs(template.FuncMap{
+	"xml": xmlEscape,
+	"ps":  func(s string) string { return strings.ReplaceAll(s, "'", "''") },
+}).Parse(`[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.UI.Notifications.ToastNotification, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+
+$template = @'
+<toast activationType="protocol" launch="{{xml .ActivationArguments}}" duration="{{if .Long}}long{{else}}short{{end}}">
+    <visual>
+        <binding template="ToastGeneric">
+            <text>{{xml .Title}}</text>
+            <text>{{xml .Message}}</text>
+        </binding>
+    </visual>
+    <audio src="ms-winsoundevent:Notification.Default" />
+{{- if .Actions}}
+    <actions>
+{{- range .Actions}}
+        <action activationType="protocol" content="{{xml .Label}}" arguments="{{xml .Arguments}}" />
+{{- end}}
+    </actions>
+{{- end}}
+</toast>
+'@
+
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml($template)
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+$toast.Tag = '{{ps .Tag}}'
+$toast.Group = '{{ps .Group}}'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('{{ps .AppID}}').Show($toast)
+`))
+
+// Push 显示通知
+// 同时运行的 PowerShell 进程数超过 maxToastProcesses 时等待；通知脚本在返回前删除（包括出错时）
+func (t *toastNotification) Push() error {
+	var script bytes.Buffer
+	if err := toastScript.Execute(&script, t); err != nil {
+		return fmt.Errorf("生成通知脚本失败: %w", err)
+	}
+
+	toastSlots <- struct{}{}
+	defer func() { <-toastSlots }()
+
+	file, err := os.CreateTemp("", toastScriptPattern)
+	if err != nil {
+		return fmt.Errorf("创建通知脚本失败: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	// Windows PowerShell 需要 BOM 才能按 UTF-8 读取脚本
+	_, err = file.Write(append([]byte{0xEF, 0xBB, 0xBF}, script.Bytes()...))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("写入通知脚本失败: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), toastScriptTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "PowerShell", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File", file.Name())
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// removeStaleToastScripts 删除之前的进程被中断（如强制退出）时遗留在临时目录中的通知脚本
+// 只删除超过运行时间上限的脚本，不影响正在显示的通知
+func removeStaleToastScripts() {
+	matches, err := filepath.Glob(filepath.Join(os.TempDir(), toastScriptPattern))
+	if err != nil {
+		return
+	}
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || time.Since(info.ModTime()) < toastScriptTimeout {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			logger.Debugf("删除遗留的通知脚本 %s 失败: %v", path, err)
+		}
+	}
+}
+
+// toastTag 把会话标识转换为 Toast 的 Tag，超过长度限制时使用哈希
+func toastTag(key string) string {
+	if len(key) <= maxToastTagLength {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:maxToastTagLength/2])
+}
+
+// xmlEscape 转义 XML 文本和属性值
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
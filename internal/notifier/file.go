@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"notifyme/pkg/types"
+)
+
+func init() {
+	RegisterSink("file", func(cfg types.SinkConfig) (Sink, error) {
+		return NewFileNotifier(cfg.Path)
+	})
+}
+
+// FileNotifier 把通知以 JSON Lines 格式追加写入文件
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNotifier 创建新的文件通知器
+func NewFileNotifier(path string) (*FileNotifier, error) {
+	if path == "" {
+		return nil, fmt.Errorf("输出文件路径未设置")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+	return &FileNotifier{path: path}, nil
+}
+
+// Notify 写入通知
+func (n *FileNotifier) Notify(notification *types.Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开文件失败: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return nil
+}
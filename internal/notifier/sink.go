@@ -0,0 +1,294 @@
+package notifier
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/pkg/types"
+)
+
+// Sink 通知渠道
+// 每个渠道负责把一条通知投递到一个目的地（桌面通知、webhook、文件等）
+type Sink interface {
+	// Notify 投递一条通知
+	Notify(notification *types.Notification) error
+}
+
+// ActionOpenApp 打开应用主界面的动作，用于汇总通知（通知上的其他动作见 types.ActionOpen 等）
+const ActionOpenApp = "open-app"
+
+// AppLink 打开应用主界面的链接，汇总通知使用该链接
+const AppLink = "notifyme://open"
+
+// ActionHandler 执行用户在通知上触发的动作，返回需要在浏览器中打开的链接（没有时为空）
+type ActionHandler func(notificationID, action string) (string, error)
+
+var (
+	actionHandlerMu sync.RWMutex
+	actionHandler   ActionHandler
+)
+
+// SetActionHandler 设置通知动作回调，支持动作的渠道在用户点击按钮时调用
+func SetActionHandler(handler ActionHandler) {
+	actionHandlerMu.Lock()
+	defer actionHandlerMu.Unlock()
+	actionHandler = handler
+}
+
+// DispatchAction 把动作转交给回调执行，由支持动作的渠道在用户点击按钮时调用
+// 返回的链接由渠道自行打开（如 Linux 上调用 xdg-open）
+func DispatchAction(notificationID, action string) (string, error) {
+	actionHandlerMu.RLock()
+	handler := actionHandler
+	actionHandlerMu.RUnlock()
+
+	if handler == nil {
+		return "", fmt.Errorf("未设置通知动作回调，忽略动作 %s: %s", action, notificationID)
+	}
+	return handler(notificationID, action)
+}
+
+// SinkFactory 根据渠道配置创建通知渠道
+type SinkFactory func(cfg types.SinkConfig) (Sink, error)
+
+var (
+	sinkFactoriesMu sync.RWMutex
+	sinkFactories   = make(map[string]SinkFactory)
+)
+
+// RegisterSink 注册通知渠道类型
+// 一般在渠道实现文件的 init 中调用，重复注册同类型渠道会 panic
+func RegisterSink(sinkType string, factory SinkFactory) {
+	sinkFactoriesMu.Lock()
+	defer sinkFactoriesMu.Unlock()
+
+	if factory == nil {
+		panic("notifier: RegisterSink factory is nil")
+	}
+	if _, exists := sinkFactories[sinkType]; exists {
+		panic(fmt.Sprintf("notifier: 通知渠道类型 %s 重复注册", sinkType))
+	}
+	sinkFactories[sinkType] = factory
+}
+
+// newSink 根据配置创建通知渠道
+func newSink(cfg types.SinkConfig) (Sink, error) {
+	sinkFactoriesMu.RLock()
+	factory, ok := sinkFactories[cfg.Type]
+	sinkFactoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("不支持的通知渠道类型: %s", cfg.Type)
+	}
+	return factory(cfg)
+}
+
+// Result 一条通知在一个渠道上的投递结果
+type Result struct {
+	Sink           string `json:"sink"`
+	NotificationID string `json:"notification_id"`
+	Error          string `json:"error,omitempty"` // 为空表示投递成功
+}
+
+// SinkStatus 通知渠道的投递统计
+type SinkStatus struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Delivered     int    `json:"delivered"`       // 成功投递次数
+	Failed        int    `json:"failed"`          // 投递失败次数
+	LastSuccessAt int64  `json:"last_success_at"` // 最近一次成功时间（Unix 秒）
+	LastErrorAt   int64  `json:"last_error_at"`   // 最近一次失败时间（Unix 秒）
+	LastError     string `json:"last_error"`      // 最近一次失败原因
+}
+
+// sinkEntry 分发器中的一个渠道
+type sinkEntry struct {
+	cfg    types.SinkConfig
+	sink   Sink
+	mu     sync.Mutex
+	status SinkStatus
+}
+
+// Dispatcher 通知分发器，把同一条通知并行投递到所有启用的渠道
+type Dispatcher struct {
+	entries []*sinkEntry
+	mu      sync.RWMutex
+}
+
+// NewDispatcher 根据渠道配置创建分发器
+func NewDispatcher(cfgs []types.SinkConfig) *Dispatcher {
+	d := &Dispatcher{}
+	d.Reconfigure(cfgs)
+	return d
+}
+
+// Reconfigure 更新渠道配置
+// 配置未变化的渠道会复用原有实例，保留其内部状态（如已通知记录）和投递统计
+func (d *Dispatcher) Reconfigure(cfgs []types.SinkConfig) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing := make(map[string]*sinkEntry, len(d.entries))
+	for _, entry := range d.entries {
+		existing[entry.cfg.Name] = entry
+	}
+
+	entries := make([]*sinkEntry, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if !cfg.Enabled {
+			continue
+		}
+		if cfg.Name == "" {
+			cfg.Name = cfg.Type
+		}
+
+		if old, ok := existing[cfg.Name]; ok && reflect.DeepEqual(old.cfg, cfg) {
+			entries = append(entries, old)
+			continue
+		}
+
+		sink, err := newSink(cfg)
+		if err != nil {
+			logger.Errorf("创建通知渠道 %s 失败: %v", cfg.Name, err)
+			continue
+		}
+		entries = append(entries, &sinkEntry{
+			cfg:    cfg,
+			sink:   sink,
+			status: SinkStatus{Name: cfg.Name, Type: cfg.Type},
+		})
+		logger.Infof("通知渠道已启用: %s (%s)", cfg.Name, cfg.Type)
+	}
+
+	// 关闭不再使用的渠道
+	kept := make(map[*sinkEntry]bool, len(entries))
+	for _, entry := range entries {
+		kept[entry] = true
+	}
+	for _, old := range d.entries {
+		if kept[old] {
+			continue
+		}
+		if closer, ok := old.sink.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				logger.Warnf("关闭通知渠道 %s 失败: %v", old.cfg.Name, err)
+			}
+		}
+	}
+
+	d.entries = entries
+}
+
+// NotifyBatch 把一批通知投递到所有渠道，返回每条通知在每个渠道上的投递结果
+// 各渠道并行投递，单个渠道内按顺序投递，某个渠道失败不影响其他渠道
+func (d *Dispatcher) NotifyBatch(notifications []*types.Notification) []Result {
+	return d.NotifyBatchTo(notifications, nil)
+}
+
+// NotifyBatchTo 把一批通知投递到指定名称的渠道，sinkNames 为空时投递到所有渠道
+// 指定的渠道都不可用时，每条通知都返回一条失败的投递结果
+func (d *Dispatcher) NotifyBatchTo(notifications []*types.Notification, sinkNames []string) []Result {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	d.mu.RLock()
+	entries := make([]*sinkEntry, 0, len(d.entries))
+	for _, entry := range d.entries {
+		if len(sinkNames) == 0 || containsName(sinkNames, entry.cfg.Name) {
+			entries = append(entries, entry)
+		}
+	}
+	d.mu.RUnlock()
+
+	if len(entries) == 0 {
+		if len(sinkNames) == 0 {
+			logger.Warn("没有启用的通知渠道，跳过投递")
+			return nil
+		}
+		// 规则指定的渠道都不可用（未启用，或如守护进程没有桌面通知），视为投递失败，避免通知被记为已投递
+		err := fmt.Sprintf("指定的通知渠道不可用: %s", strings.Join(sinkNames, ", "))
+		logger.Warnf("%s，%d 条通知未投递", err, len(notifications))
+		results := make([]Result, 0, len(notifications))
+		for _, notification := range notifications {
+			results = append(results, Result{Sink: strings.Join(sinkNames, ","), NotificationID: notification.ID, Error: err})
+		}
+		return results
+	}
+
+	var (
+		results   []Result
+		resultsMu sync.Mutex
+		wg        sync.WaitGroup
+	)
+	for _, entry := range entries {
+		wg.Add(1)
+		go func(entry *sinkEntry) {
+			defer wg.Done()
+			sinkResults := entry.deliver(notifications)
+			resultsMu.Lock()
+			results = append(results, sinkResults...)
+			resultsMu.Unlock()
+		}(entry)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Status 获取所有渠道的投递统计
+func (d *Dispatcher) Status() []SinkStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	result := make([]SinkStatus, 0, len(d.entries))
+	for _, entry := range d.entries {
+		entry.mu.Lock()
+		result = append(result, entry.status)
+		entry.mu.Unlock()
+	}
+	return result
+}
+
+// containsName 检查渠道名称列表是否包含指定名称
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver 把一批通知投递到该渠道
+func (e *sinkEntry) deliver(notifications []*types.Notification) []Result {
+	results := make([]Result, 0, len(notifications))
+	for _, notification := range notifications {
+		result := Result{Sink: e.cfg.Name, NotificationID: notification.ID}
+		err := e.sink.Notify(notification)
+
+		e.mu.Lock()
+		if err != nil {
+			e.status.Failed++
+			e.status.LastErrorAt = time.Now().Unix()
+			e.status.LastError = err.Error()
+			result.Error = err.Error()
+		} else {
+			e.status.Delivered++
+			e.status.LastSuccessAt = time.Now().Unix()
+		}
+		e.mu.Unlock()
+
+		if err != nil {
+			// 继续投递其他通知，不中断
+			logger.Errorf("通知渠道 %s 投递失败: %v", e.cfg.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/pkg/types"
+)
+
+func init() {
+	RegisterSink("webhook", func(cfg types.SinkConfig) (Sink, error) {
+		return NewWebhookNotifier(cfg.URL, cfg.Headers)
+	})
+}
+
+// WebhookNotifier 以 JSON 形式把通知 POST 到指定地址
+type WebhookNotifier struct {
+	url        string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier 创建新的 webhook 通知器
+func NewWebhookNotifier(url string, headers map[string]string) (*WebhookNotifier, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook 地址未设置")
+	}
+	return &WebhookNotifier{
+		url:     url,
+		headers: headers,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Notify 发送通知
+func (n *WebhookNotifier) Notify(notification *types.Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NotifyMe/1.0")
+	for key, value := range n.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook 返回错误状态码 %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	logger.Debugf("已推送通知到 webhook: %s", notification.ID)
+	return nil
+}
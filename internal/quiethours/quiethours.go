@@ -0,0 +1,109 @@
+package quiethours
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // Windows 上可能没有时区数据库，内置一份
+
+	"notifyme/pkg/types"
+)
+
+// window 解析后的勿扰时间段
+type window struct {
+	days  map[time.Weekday]bool // 为空表示每天
+	start int                   // 开始时间（当天第几分钟）
+	end   int                   // 结束时间（当天第几分钟）
+}
+
+// Schedule 勿扰时段
+type Schedule struct {
+	location *time.Location
+	windows  []window
+}
+
+// New 解析勿扰时段配置，未启用时返回的 Schedule 永远不处于勿扰状态
+func New(cfg types.QuietHoursConfig) (*Schedule, error) {
+	s := &Schedule{location: time.Local}
+	if cfg.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("无效的时区 %s: %w", cfg.Timezone, err)
+		}
+		s.location = location
+	}
+
+	for i, w := range cfg.Windows {
+		start, err := parseClock(w.Start)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个勿扰时间段开始时间无效: %w", i+1, err)
+		}
+		end, err := parseClock(w.End)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个勿扰时间段结束时间无效: %w", i+1, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("第 %d 个勿扰时间段开始时间和结束时间相同", i+1)
+		}
+
+		parsed := window{start: start, end: end}
+		for _, day := range w.Days {
+			if day < 0 || day > 6 {
+				return nil, fmt.Errorf("第 %d 个勿扰时间段星期无效: %d", i+1, day)
+			}
+			if parsed.days == nil {
+				parsed.days = make(map[time.Weekday]bool)
+			}
+			parsed.days[time.Weekday(day)] = true
+		}
+		if cfg.Enabled {
+			s.windows = append(s.windows, parsed)
+		}
+	}
+	return s, nil
+}
+
+// Validate 检查勿扰时段配置是否有效
+func Validate(cfg types.QuietHoursConfig) error {
+	_, err := New(cfg)
+	return err
+}
+
+// Active 检查指定时间是否处于勿扰时段
+// 跨越午夜的时间段归属于开始那一天，如周五 22:00-07:00 包含周六凌晨
+func (s *Schedule) Active(t time.Time) bool {
+	t = t.In(s.location)
+	minute := t.Hour()*60 + t.Minute()
+	today := t.Weekday()
+	yesterday := (today + 6) % 7
+
+	for _, w := range s.windows {
+		if w.start < w.end {
+			if minute >= w.start && minute < w.end && w.onDay(today) {
+				return true
+			}
+			continue
+		}
+		// 跨越午夜
+		if minute >= w.start && w.onDay(today) {
+			return true
+		}
+		if minute < w.end && w.onDay(yesterday) {
+			return true
+		}
+	}
+	return false
+}
+
+// onDay 检查时间段是否在指定星期生效
+func (w window) onDay(day time.Weekday) bool {
+	return w.days == nil || w.days[day]
+}
+
+// parseClock 解析 HH:MM 格式的时间，返回当天第几分钟
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("时间格式应为 HH:MM: %s", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
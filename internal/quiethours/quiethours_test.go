@@ -0,0 +1,143 @@
+package quiethours
+
+import (
+	"testing"
+	"time"
+
+	"notifyme/pkg/types"
+)
+
+// at 解析指定时区的时间，测试中 2024-01-05 是星期五
+func at(t *testing.T, location string, value string) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func mustNew(t *testing.T, cfg types.QuietHoursConfig) *Schedule {
+	t.Helper()
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New 失败: %v", err)
+	}
+	return s
+}
+
+func TestActive(t *testing.T) {
+	friday := int(time.Friday)
+
+	tests := []struct {
+		name string
+		cfg  types.QuietHoursConfig
+		time string
+		want bool
+	}{
+		// 周五 22:00-07:00：跨越午夜的时间段归属于周五
+		{"周五夜间开始前", overnight(friday), "2024-01-05 21:59", false},
+		{"周五夜间开始", overnight(friday), "2024-01-05 22:00", true},
+		{"周六凌晨结束前", overnight(friday), "2024-01-06 06:59", true},
+		{"周六凌晨结束", overnight(friday), "2024-01-06 07:00", false},
+		{"周五凌晨不属于周五的时间段", overnight(friday), "2024-01-05 06:00", false},
+		{"周六夜间不生效", overnight(friday), "2024-01-06 23:00", false},
+
+		// 不跨越午夜，按星期过滤
+		{"工作日白天", daytime(1, 2, 3, 4, 5), "2024-01-05 10:00", true},
+		{"周末白天不生效", daytime(1, 2, 3, 4, 5), "2024-01-06 10:00", false},
+		{"结束时间不包含在内", daytime(1, 2, 3, 4, 5), "2024-01-05 18:00", false},
+		{"没有指定星期表示每天", daytime(), "2024-01-07 09:00", true},
+
+		{"未启用时永远不处于勿扰状态", types.QuietHoursConfig{
+			Windows: []types.QuietWindow{{Start: "00:00", End: "23:59"}},
+		}, "2024-01-05 12:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := mustNew(t, tt.cfg)
+			if got := s.Active(at(t, "UTC", tt.time)); got != tt.want {
+				t.Errorf("Active(%s) = %v, want %v", tt.time, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActiveTimezone(t *testing.T) {
+	// 配置为上海时间 22:00-07:00，UTC 14:00 即上海 22:00
+	cfg := types.QuietHoursConfig{
+		Enabled:  true,
+		Timezone: "Asia/Shanghai",
+		Windows:  []types.QuietWindow{{Days: []int{int(time.Friday)}, Start: "22:00", End: "07:00"}},
+	}
+	s := mustNew(t, cfg)
+
+	tests := []struct {
+		time string
+		want bool
+	}{
+		{"2024-01-05 13:59", false}, // 上海周五 21:59
+		{"2024-01-05 14:00", true},  // 上海周五 22:00
+		{"2024-01-05 22:59", true},  // 上海周六 06:59
+		{"2024-01-05 23:00", false}, // 上海周六 07:00
+	}
+	for _, tt := range tests {
+		if got := s.Active(at(t, "UTC", tt.time)); got != tt.want {
+			t.Errorf("Active(%s UTC) = %v, want %v", tt.time, got, tt.want)
+		}
+	}
+
+	// 同一时刻用其他时区表示结果相同
+	if !s.Active(at(t, "America/New_York", "2024-01-05 09:00")) {
+		t.Error("纽约周五 09:00（上海周五 22:00）应处于勿扰时段")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     types.QuietHoursConfig
+		wantErr bool
+	}{
+		{"有效配置", overnight(int(time.Friday)), false},
+		{"开始时间和结束时间相同", window1("08:00", "08:00", nil), true},
+		{"时间格式无效", window1("8点", "09:00", nil), true},
+		{"时间超出范围", window1("22:00", "24:00", nil), true},
+		{"星期无效", window1("22:00", "07:00", []int{7}), true},
+		{"时区无效", types.QuietHoursConfig{Enabled: true, Timezone: "Mars/Olympus"}, true},
+		{"未启用的配置同样检查", types.QuietHoursConfig{Windows: []types.QuietWindow{{Start: "08:00", End: "08:00"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// overnight 在指定星期 22:00-07:00 勿扰
+func overnight(days ...int) types.QuietHoursConfig {
+	return window1("22:00", "07:00", days)
+}
+
+// daytime 在指定星期 09:00-18:00 勿扰
+func daytime(days ...int) types.QuietHoursConfig {
+	return window1("09:00", "18:00", days)
+}
+
+// window1 只有一个时间段的启用配置（时区为 UTC）
+func window1(start, end string, days []int) types.QuietHoursConfig {
+	return types.QuietHoursConfig{
+		Enabled:  true,
+		Timezone: "UTC",
+		Windows:  []types.QuietWindow{{Days: days, Start: start, End: end}},
+	}
+}
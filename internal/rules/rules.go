@@ -0,0 +1,174 @@
+package rules
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"notifyme/pkg/types"
+)
+
+// 规则动作
+const (
+	ActionDrop     = "drop"     // 丢弃通知，不投递也不保存
+	ActionMute     = "mute"     // 不投递，但保存到通知历史
+	ActionRoute    = "route"    // 只投递到指定渠道
+	ActionPriority = "priority" // 设置通知优先级
+)
+
+// 通知优先级
+const (
+	PriorityLow    = "low"
+	PriorityNormal = "normal"
+	PriorityHigh   = "high"
+)
+
+// Decision 规则对一条通知的处理结果
+type Decision struct {
+	Drop     bool     // 丢弃通知
+	Mute     bool     // 不投递通知
+	Sinks    []string // 只投递到这些渠道，为空表示所有渠道
+	Priority string   // 优先级，为空表示不修改
+	Rules    []string // 命中的规则名称
+}
+
+// rule 编译后的规则
+type rule struct {
+	types.Rule
+	title   *regexp.Regexp
+	content *regexp.Regexp
+}
+
+// Engine 规则引擎
+// 所有命中的规则按顺序生效：drop 立即终止；route 的渠道取并集；priority 以最后一条为准
+type Engine struct {
+	rules []rule
+}
+
+// New 编译规则并创建规则引擎，未启用的规则会被跳过
+func New(rules []types.Rule) (*Engine, error) {
+	e := &Engine{}
+	for i, r := range rules {
+		if err := validate(r); err != nil {
+			return nil, fmt.Errorf("第 %d 条规则 %s 无效: %w", i+1, r.Name, err)
+		}
+		if !r.Enabled {
+			continue
+		}
+
+		compiled := rule{Rule: r}
+		if r.Match.Title != "" {
+			compiled.title = regexp.MustCompile(r.Match.Title)
+		}
+		if r.Match.Content != "" {
+			compiled.content = regexp.MustCompile(r.Match.Content)
+		}
+		e.rules = append(e.rules, compiled)
+	}
+	return e, nil
+}
+
+// Validate 检查规则配置是否有效
+func Validate(rules []types.Rule) error {
+	_, err := New(rules)
+	return err
+}
+
+// Evaluate 对一条通知执行所有规则
+func (e *Engine) Evaluate(notification *types.Notification) Decision {
+	var decision Decision
+	for _, r := range e.rules {
+		if !r.match(notification) {
+			continue
+		}
+		decision.Rules = append(decision.Rules, r.Name)
+
+		switch r.Action {
+		case ActionDrop:
+			decision.Drop = true
+			return decision
+		case ActionMute:
+			decision.Mute = true
+		case ActionRoute:
+			for _, sink := range r.Sinks {
+				if !contains(decision.Sinks, sink) {
+					decision.Sinks = append(decision.Sinks, sink)
+				}
+			}
+		case ActionPriority:
+			decision.Priority = r.Priority
+		}
+	}
+	return decision
+}
+
+// match 检查通知是否满足规则的所有匹配条件
+func (r *rule) match(notification *types.Notification) bool {
+	m := r.Match
+	if m.Source != "" && !strings.EqualFold(m.Source, notification.Source) {
+		return false
+	}
+	if m.Repository != "" {
+		if ok, _ := path.Match(strings.ToLower(m.Repository), strings.ToLower(notification.Repository)); !ok {
+			return false
+		}
+	}
+	if m.Reason != "" && !strings.EqualFold(m.Reason, notification.Reason) {
+		return false
+	}
+	if m.Kind != "" && !strings.EqualFold(m.Kind, notification.Kind) {
+		return false
+	}
+	if r.title != nil && !r.title.MatchString(notification.Title) {
+		return false
+	}
+	if r.content != nil && !r.content.MatchString(notification.Content) {
+		return false
+	}
+	return true
+}
+
+// validate 检查单条规则
+func validate(r types.Rule) error {
+	switch r.Action {
+	case ActionDrop, ActionMute:
+	case ActionRoute:
+		if len(r.Sinks) == 0 {
+			return fmt.Errorf("route 动作需要指定渠道")
+		}
+	case ActionPriority:
+		if r.Priority != PriorityLow && r.Priority != PriorityNormal && r.Priority != PriorityHigh {
+			return fmt.Errorf("无效的优先级: %s", r.Priority)
+		}
+	default:
+		return fmt.Errorf("无效的动作: %s", r.Action)
+	}
+
+	if r.Match.Title != "" {
+		if _, err := regexp.Compile(r.Match.Title); err != nil {
+			return fmt.Errorf("标题正则表达式无效: %w", err)
+		}
+	}
+	if r.Match.Content != "" {
+		if _, err := regexp.Compile(r.Match.Content); err != nil {
+			return fmt.Errorf("内容正则表达式无效: %w", err)
+		}
+	}
+	if r.Match.Repository != "" {
+		if _, err := path.Match(r.Match.Repository, ""); err != nil {
+			return fmt.Errorf("仓库通配符无效: %w", err)
+		}
+	}
+	return nil
+}
+
+// contains 检查字符串切片是否包含指定值
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
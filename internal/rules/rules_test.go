@@ -0,0 +1,142 @@
+package rules
+
+import (
+	"reflect"
+	"testing"
+
+	"notifyme/pkg/types"
+)
+
+func TestEvaluate(t *testing.T) {
+	pr := &types.Notification{
+		Source:     "github",
+		Title:      "Fix login bug",
+		Content:    "CI failed on main",
+		Repository: "octo/app",
+		Reason:     "ci_activity",
+		Kind:       "PullRequest",
+	}
+
+	tests := []struct {
+		name  string
+		rules []types.Rule
+		want  Decision
+	}{
+		{
+			name:  "没有规则",
+			rules: nil,
+			want:  Decision{},
+		},
+		{
+			name: "drop 立即终止，之后的规则不再生效",
+			rules: []types.Rule{
+				{Name: "mute", Enabled: true, Action: ActionMute},
+				{Name: "drop", Enabled: true, Match: types.RuleMatch{Reason: "ci_activity"}, Action: ActionDrop},
+				{Name: "high", Enabled: true, Action: ActionPriority, Priority: PriorityHigh},
+			},
+			want: Decision{Mute: true, Drop: true, Rules: []string{"mute", "drop"}},
+		},
+		{
+			name: "route 的渠道取并集",
+			rules: []types.Rule{
+				{Name: "a", Enabled: true, Action: ActionRoute, Sinks: []string{"desktop", "webhook"}},
+				{Name: "b", Enabled: true, Action: ActionRoute, Sinks: []string{"webhook", "file"}},
+			},
+			want: Decision{Sinks: []string{"desktop", "webhook", "file"}, Rules: []string{"a", "b"}},
+		},
+		{
+			name: "priority 以最后一条为准",
+			rules: []types.Rule{
+				{Name: "high", Enabled: true, Action: ActionPriority, Priority: PriorityHigh},
+				{Name: "low", Enabled: true, Action: ActionPriority, Priority: PriorityLow},
+			},
+			want: Decision{Priority: PriorityLow, Rules: []string{"high", "low"}},
+		},
+		{
+			name: "跳过未启用的规则",
+			rules: []types.Rule{
+				{Name: "disabled", Enabled: false, Action: ActionDrop},
+				{Name: "mute", Enabled: true, Action: ActionMute},
+			},
+			want: Decision{Mute: true, Rules: []string{"mute"}},
+		},
+		{
+			name: "来源、原因和类型不区分大小写",
+			rules: []types.Rule{
+				{Name: "match", Enabled: true, Match: types.RuleMatch{Source: "GitHub", Reason: "CI_Activity", Kind: "pullrequest"}, Action: ActionMute},
+			},
+			want: Decision{Mute: true, Rules: []string{"match"}},
+		},
+		{
+			name: "来源不匹配",
+			rules: []types.Rule{
+				{Name: "ld246", Enabled: true, Match: types.RuleMatch{Source: "ld246"}, Action: ActionMute},
+			},
+			want: Decision{},
+		},
+		{
+			name: "仓库通配符",
+			rules: []types.Rule{
+				{Name: "owner", Enabled: true, Match: types.RuleMatch{Repository: "Octo/*"}, Action: ActionMute},
+				{Name: "other", Enabled: true, Match: types.RuleMatch{Repository: "other/*"}, Action: ActionDrop},
+			},
+			want: Decision{Mute: true, Rules: []string{"owner"}},
+		},
+		{
+			name: "标题和内容正则表达式",
+			rules: []types.Rule{
+				{Name: "title", Enabled: true, Match: types.RuleMatch{Title: `(?i)^fix`}, Action: ActionPriority, Priority: PriorityHigh},
+				{Name: "content", Enabled: true, Match: types.RuleMatch{Content: `succeeded`}, Action: ActionDrop},
+			},
+			want: Decision{Priority: PriorityHigh, Rules: []string{"title"}},
+		},
+		{
+			name: "所有条件都满足才命中",
+			rules: []types.Rule{
+				{Name: "both", Enabled: true, Match: types.RuleMatch{Source: "github", Kind: "Issue"}, Action: ActionDrop},
+			},
+			want: Decision{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := New(tt.rules)
+			if err != nil {
+				t.Fatalf("New 失败: %v", err)
+			}
+			if got := engine.Evaluate(pr); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    types.Rule
+		wantErr bool
+	}{
+		{"有效的丢弃规则", types.Rule{Action: ActionDrop}, false},
+		{"有效的路由规则", types.Rule{Action: ActionRoute, Sinks: []string{"desktop"}}, false},
+		{"有效的优先级规则", types.Rule{Action: ActionPriority, Priority: PriorityNormal}, false},
+		{"无效的标题正则表达式", types.Rule{Action: ActionMute, Match: types.RuleMatch{Title: "("}}, true},
+		{"无效的内容正则表达式", types.Rule{Action: ActionMute, Match: types.RuleMatch{Content: "[a-"}}, true},
+		{"无效的仓库通配符", types.Rule{Action: ActionMute, Match: types.RuleMatch{Repository: "octo/["}}, true},
+		{"无效的动作", types.Rule{Action: "notify"}, true},
+		{"缺少动作", types.Rule{}, true},
+		{"无效的优先级", types.Rule{Action: ActionPriority, Priority: "urgent"}, true},
+		{"路由规则没有渠道", types.Rule{Action: ActionRoute}, true},
+		{"未启用的规则同样检查", types.Rule{Enabled: false, Action: ActionMute, Match: types.RuleMatch{Title: "("}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]types.Rule{tt.rule})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	"notifyme/pkg/types"
+)
+
+// DefaultSnoozeDuration 通过通知动作设置稍后提醒、没有指定时长时的默认时长
+const DefaultSnoozeDuration = time.Hour
+
+// ErrUnsupportedAction 动作不存在或通知不支持该动作
+var ErrUnsupportedAction = errors.New("通知不支持该动作")
+
+// ExecuteAction 执行通知上的动作，返回需要在浏览器中打开的链接（没有时为空）
+// 桌面通知按钮、应用链接、主界面和本机接口都通过这里执行动作；snooze 为稍后提醒的时长，只用于稍后提醒，为 0 时使用默认时长；
+// 通知声明了动作列表时只允许执行其中的动作，旧版本保存的通知没有动作列表，允许执行所有内置动作
+func (s *Scheduler) ExecuteAction(notificationID, actionID string, snooze time.Duration) (string, error) {
+	notification, err := s.GetNotification(notificationID)
+	if err != nil {
+		return "", err
+	}
+
+	action := notification.Action(actionID)
+	if action == nil && len(notification.Actions) > 0 {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAction, actionID)
+	}
+	logger.Infof("执行通知动作 %s: %s", actionID, notificationID)
+
+	switch actionID {
+	case types.ActionOpen:
+		return actionURL(notification, action), s.MarkRead(notificationID)
+	case types.ActionMarkRead:
+		return "", s.MarkRead(notificationID)
+	case types.ActionMarkDone:
+		return "", s.Archive(notificationID)
+	case types.ActionMuteThread:
+		return "", s.MuteThread(notificationID)
+	case types.ActionSnooze:
+		if snooze <= 0 {
+			snooze = DefaultSnoozeDuration
+		}
+		return "", s.Snooze(notificationID, time.Now().Add(snooze))
+	case types.ActionReply:
+		url := actionURL(notification, action)
+		if url == "" {
+			return "", fmt.Errorf("%w: %s（通知没有可以回复的链接）", ErrUnsupportedAction, actionID)
+		}
+		return url, s.MarkRead(notificationID)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedAction, actionID)
+	}
+}
+
+// actionURL 动作需要打开的链接，动作没有指定链接时使用通知的链接
+func actionURL(notification *types.Notification, action *types.NotificationAction) string {
+	if action != nil && action.URL != "" {
+		return action.URL
+	}
+	return notification.Link
+}
+
+// handleNotificationAction 处理通知渠道上触发的动作
+func (s *Scheduler) handleNotificationAction(id, action string) (string, error) {
+	if action == notifier.ActionOpenApp {
+		s.mu.RLock()
+		handler := s.onOpenApp
+		s.mu.RUnlock()
+		if handler != nil {
+			handler()
+		}
+		return "", nil
+	}
+	return s.ExecuteAction(id, action, 0)
+}
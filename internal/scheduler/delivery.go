@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"strings"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	"notifyme/internal/rules"
+	"notifyme/pkg/types"
+)
+
+// setRules 设置通知规则
+func (s *Scheduler) setRules(ruleList []types.Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setRulesLocked(ruleList)
+}
+
+// setRulesLocked 设置通知规则，规则无效时不使用任何规则（调用方需持有 s.mu 写锁）
+func (s *Scheduler) setRulesLocked(ruleList []types.Rule) {
+	engine, err := rules.New(ruleList)
+	if err != nil {
+		logger.Errorf("加载通知规则失败: %v", err)
+		engine, _ = rules.New(nil)
+	}
+	s.ruleEngine = engine
+}
+
+// applyRules 对通知执行规则，返回保留的通知（被丢弃的通知不再投递和保存）及每条通知的处理结果
+func (s *Scheduler) applyRules(notifications []*types.Notification) ([]*types.Notification, map[string]rules.Decision) {
+	s.mu.RLock()
+	engine := s.ruleEngine
+	s.mu.RUnlock()
+
+	muted, err := s.store.MutedThreads(notifications)
+	if err != nil {
+		logger.Warnf("读取静音会话失败: %v", err)
+	}
+
+	kept := make([]*types.Notification, 0, len(notifications))
+	decisions := make(map[string]rules.Decision, len(notifications))
+	dropped := 0
+	for _, notification := range notifications {
+		decision := engine.Evaluate(notification)
+		// 静音会话的更新只保存到历史，不再提醒
+		if muted[notification.ID] {
+			decision.Mute = true
+			decision.Rules = append(decision.Rules, mutedThreadRule)
+		}
+		if len(decision.Rules) > 0 {
+			logger.Debugf("通知 %s 命中规则: %s", notification.ID, strings.Join(decision.Rules, ", "))
+		}
+		if decision.Drop {
+			dropped++
+			continue
+		}
+		if decision.Priority != "" {
+			notification.Priority = decision.Priority
+		}
+		kept = append(kept, notification)
+		decisions[notification.ID] = decision
+	}
+
+	if dropped > 0 {
+		logger.Infof("规则丢弃了 %d 条通知", dropped)
+	}
+	return kept, decisions
+}
+
+// deliver 按规则投递尚未投递过的通知，并记录到投递记录中
+// 所有渠道都投递失败的通知不记录，下次拉取到时会重试
+func (s *Scheduler) deliver(notifications []*types.Notification, decisions map[string]rules.Decision) []*types.Notification {
+	pending, err := s.store.Undelivered(notifications)
+	if err != nil {
+		logger.Errorf("读取投递记录失败: %v", err)
+		return nil
+	}
+	if skipped := len(notifications) - len(pending); skipped > 0 {
+		logger.Debugf("跳过 %d 条已投递过的通知", skipped)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	delivered := s.dispatch(pending, decisions)
+	if err := s.store.MarkDelivered(delivered); err != nil {
+		logger.Errorf("写入投递记录失败: %v", err)
+	}
+	return pending
+}
+
+// dispatch 按规则的处理结果投递通知，返回可以记为已投递的通知
+// 被静音的通知不投递；勿扰期间的通知暂缓提醒（由 Defer 记录）；进入合并窗口的通知在窗口结束时记录；
+// 所有渠道都投递失败的通知不返回
+func (s *Scheduler) dispatch(pending []*types.Notification, decisions map[string]rules.Decision) []*types.Notification {
+	// 按来源和投递渠道分组，同一组的通知一起投递（并按汇总配置合并）
+	delivered := make([]*types.Notification, 0, len(pending))
+	groups := make(map[string][]*types.Notification)
+	groupSinks := make(map[string][]string)
+	var groupKeys []string
+	for _, notification := range pending {
+		decision := decisions[notification.ID]
+		if decision.Mute {
+			delivered = append(delivered, notification)
+			continue
+		}
+		key := notification.Source + "|" + strings.Join(decision.Sinks, ",")
+		if _, ok := groups[key]; !ok {
+			groupKeys = append(groupKeys, key)
+			groupSinks[key] = decision.Sinks
+		}
+		groups[key] = append(groups[key], notification)
+	}
+	if muted := len(delivered); muted > 0 {
+		logger.Infof("规则静音了 %d 条通知", muted)
+	}
+
+	// 勿扰期间暂缓提醒，结束后汇总
+	if active, reason := s.doNotDisturb(); active && len(groupKeys) > 0 {
+		var deferred []*types.Notification
+		for _, key := range groupKeys {
+			deferred = append(deferred, groups[key]...)
+		}
+		if err := s.store.Defer(deferred); err != nil {
+			logger.Errorf("暂缓提醒通知失败: %v", err)
+		} else {
+			logger.Infof("%s，暂缓提醒 %d 条通知", reason, len(deferred))
+		}
+		groupKeys = nil
+	}
+
+	for _, key := range groupKeys {
+		results, sent := s.notifyGroup(groups[key], groupSinks[key])
+		if sent {
+			delivered = append(delivered, succeeded(groups[key], results)...)
+		}
+	}
+	return delivered
+}
+
+// succeeded 根据投递结果筛选出至少有一个渠道投递成功（或没有投递到任何渠道）的通知
+func succeeded(notifications []*types.Notification, results []notifier.Result) []*types.Notification {
+	failed := make(map[string]bool)
+	ok := make(map[string]bool)
+	for _, result := range results {
+		if result.Error == "" {
+			ok[result.NotificationID] = true
+		} else {
+			failed[result.NotificationID] = true
+		}
+	}
+
+	delivered := make([]*types.Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if failed[notification.ID] && !ok[notification.ID] {
+			continue
+		}
+		delivered = append(delivered, notification)
+	}
+	return delivered
+}
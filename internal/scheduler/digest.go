@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	"notifyme/internal/rules"
+	"notifyme/pkg/types"
+)
+
+// digestPreview 汇总通知内容中最多列出的仓库或标题数量
+const digestPreview = 3
+
+// digestBuffer 合并窗口内等待汇总的通知
+type digestBuffer struct {
+	sinks         []string
+	notifications []*types.Notification
+	index         map[string]int // 通知 ID -> 在 notifications 中的位置，窗口内再次拉取到的通知替换原来的一条
+	timer         *time.Timer    // 窗口结束时投递，调度器停止时取消
+}
+
+// notifyGroup 投递同一来源、同一组渠道的新通知，按汇总配置合并为一条汇总通知
+// 按窗口合并时通知先进入缓冲区，返回 false；窗口结束后再投递并写入投递记录
+func (s *Scheduler) notifyGroup(notifications []*types.Notification, sinks []string) ([]notifier.Result, bool) {
+	s.mu.RLock()
+	digest := s.config.Digest
+	s.mu.RUnlock()
+
+	switch {
+	case !digest.Enabled:
+		return s.dispatcher.NotifyBatchTo(notifications, sinks), true
+	case digest.Window > 0 && s.bufferDigest(notifications, sinks, time.Duration(digest.Window)*time.Second):
+		return nil, false
+	case digest.Threshold > 0 && len(notifications) > digest.Threshold:
+		return s.notifyDigest(notifications, sinks), true
+	default:
+		return s.dispatcher.NotifyBatchTo(notifications, sinks), true
+	}
+}
+
+// bufferDigest 把通知放入合并窗口，窗口中的第一条通知到达时开始计时
+// 调度器已停止时不再缓冲，返回 false，由调用方直接投递
+func (s *Scheduler) bufferDigest(notifications []*types.Notification, sinks []string, window time.Duration) bool {
+	key := notifications[0].Source + "|" + strings.Join(sinks, ",")
+
+	s.digestMu.Lock()
+	defer s.digestMu.Unlock()
+
+	if s.digestClosed {
+		return false
+	}
+	buffer, ok := s.digestBuffers[key]
+	if !ok {
+		buffer = &digestBuffer{sinks: sinks, index: make(map[string]int)}
+		s.digestBuffers[key] = buffer
+		buffer.timer = time.AfterFunc(window, func() {
+			s.digestMu.Lock()
+			if s.digestClosed {
+				// 调度器正在停止，由 stopDigests 投递
+				s.digestMu.Unlock()
+				return
+			}
+			s.digestFlushes.Add(1)
+			s.digestMu.Unlock()
+
+			defer s.digestFlushes.Done()
+			s.flushDigest(key)
+		})
+	}
+	// 通知在投递后才写入投递记录，窗口内可能再次拉取到同一条通知
+	for _, notification := range notifications {
+		if i, ok := buffer.index[notification.ID]; ok {
+			buffer.notifications[i] = notification
+			continue
+		}
+		buffer.index[notification.ID] = len(buffer.notifications)
+		buffer.notifications = append(buffer.notifications, notification)
+	}
+	logger.Debugf("%d 条通知进入合并窗口 %s，共 %d 条", len(notifications), key, len(buffer.notifications))
+	return true
+}
+
+// flushDigest 合并窗口结束，投递缓冲区中的通知并写入投递记录
+// 只有一条或不超过条数阈值时逐条投递，否则合并为一条汇总
+func (s *Scheduler) flushDigest(key string) {
+	s.digestMu.Lock()
+	buffer, ok := s.digestBuffers[key]
+	delete(s.digestBuffers, key)
+	s.digestMu.Unlock()
+
+	if !ok || len(buffer.notifications) == 0 {
+		return
+	}
+
+	s.mu.RLock()
+	threshold := s.config.Digest.Threshold
+	s.mu.RUnlock()
+
+	var results []notifier.Result
+	if len(buffer.notifications) == 1 || (threshold > 0 && len(buffer.notifications) <= threshold) {
+		results = s.dispatcher.NotifyBatchTo(buffer.notifications, buffer.sinks)
+	} else {
+		results = s.notifyDigest(buffer.notifications, buffer.sinks)
+	}
+
+	if err := s.store.MarkDelivered(succeeded(buffer.notifications, results)); err != nil {
+		logger.Errorf("写入投递记录失败: %v", err)
+	}
+}
+
+// stopDigests 停止所有合并窗口的计时并等待正在进行的投递完成，然后立即投递窗口中的通知（退出前、关闭数据库之前调用）
+// 之后到达的通知不再进入合并窗口
+func (s *Scheduler) stopDigests() {
+	s.digestMu.Lock()
+	s.digestClosed = true
+	keys := make([]string, 0, len(s.digestBuffers))
+	for key, buffer := range s.digestBuffers {
+		buffer.timer.Stop()
+		keys = append(keys, key)
+	}
+	s.digestMu.Unlock()
+
+	s.digestFlushes.Wait()
+
+	for _, key := range keys {
+		s.flushDigest(key)
+	}
+}
+
+// notifyDigest 把同一来源的多条通知合并为一条汇总通知投递
+// 汇总通知的投递结果展开到每一条被汇总的通知上，汇总投递失败时这些通知都视为投递失败
+func (s *Scheduler) notifyDigest(notifications []*types.Notification, sinks []string) []notifier.Result {
+	digest := digestNotification(notifications)
+	logger.Infof("合并 %d 条 %s 通知为一条汇总", len(notifications), notifications[0].Source)
+
+	var results []notifier.Result
+	for _, result := range s.dispatcher.NotifyBatchTo([]*types.Notification{digest}, sinks) {
+		for _, notification := range notifications {
+			result.NotificationID = notification.ID
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// digestNotification 生成同一来源的汇总通知，如 "12 条新的 github 通知，来自 4 个仓库"
+// 点击汇总通知打开应用主界面
+func digestNotification(notifications []*types.Notification) *types.Notification {
+	source := notifications[0].Source
+
+	var repos, titles []string
+	seenRepos := make(map[string]bool)
+	priority := ""
+	for _, notification := range notifications {
+		if notification.Repository != "" && !seenRepos[notification.Repository] {
+			seenRepos[notification.Repository] = true
+			repos = append(repos, notification.Repository)
+		}
+		titles = append(titles, notification.Title)
+		if notification.Priority == rules.PriorityHigh {
+			priority = rules.PriorityHigh
+		}
+	}
+
+	var content string
+	if len(repos) > 0 {
+		content = fmt.Sprintf("来自 %d 个仓库：%s", len(repos), previewList(repos, "、"))
+	} else {
+		content = previewList(titles, "；")
+	}
+
+	now := time.Now()
+	return &types.Notification{
+		ID:       fmt.Sprintf("notifyme_digest_%s_%d", source, now.UnixMilli()),
+		Title:    fmt.Sprintf("%d 条新的 %s 通知", len(notifications), sourceLabel(source)),
+		Content:  content,
+		Link:     notifier.AppLink,
+		Source:   "notifyme",
+		Priority: priority,
+		Time:     now.UnixMilli(),
+	}
+}
+
+// previewList 列出前 digestPreview 项，超出时以 "等" 结尾
+func previewList(items []string, sep string) string {
+	if len(items) <= digestPreview {
+		return strings.Join(items, sep)
+	}
+	return strings.Join(items[:digestPreview], sep) + " 等"
+}
@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"notifyme/internal/eventbus"
+	"notifyme/internal/logger"
+)
+
+// HealthState 数据源健康状态
+type HealthState string
+
+const (
+	HealthOK       HealthState = "ok"       // 正常
+	HealthDegraded HealthState = "degraded" // 最近请求失败，正在退避重试
+	HealthDown     HealthState = "down"     // 连续失败次数过多，熔断后按最大退避间隔探测
+)
+
+const (
+	downThreshold = 5                // 连续失败达到该次数后进入 down 状态
+	maxBackoff    = 30 * time.Minute // 最大退避间隔
+	backoffJitter = 0.2              // 退避间隔的随机抖动比例（±20%）
+)
+
+// sourceHealth 数据源的健康记录（由 s.mu 保护）
+type sourceHealth struct {
+	failures      int // 连续失败次数
+	lastSuccessAt time.Time
+	lastErrorAt   time.Time
+	lastError     string
+}
+
+// state 根据连续失败次数计算健康状态
+func (h *sourceHealth) state() HealthState {
+	switch {
+	case h.failures == 0:
+		return HealthOK
+	case h.failures < downThreshold:
+		return HealthDegraded
+	default:
+		return HealthDown
+	}
+}
+
+// backoff 计算连续失败 failures 次后的退避间隔：interval * 2^failures，不超过 maxBackoff，并加入随机抖动
+func backoff(interval time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	jitter := (rand.Float64()*2 - 1) * backoffJitter
+	return delay + time.Duration(float64(delay)*jitter)
+}
+
+// recordSuccess 记录数据源检查成功
+func (s *Scheduler) recordSuccess(name string) {
+	s.mu.Lock()
+	h := s.healthLocked(name)
+	before := h.state()
+	h.failures = 0
+	h.lastSuccessAt = time.Now()
+	s.mu.Unlock()
+
+	if before != HealthOK {
+		logger.Infof("%s 已恢复正常", name)
+		eventbus.Publish(eventbus.HealthChanged, eventbus.HealthEvent{Source: name, Health: string(HealthOK)})
+	}
+}
+
+// recordFailure 记录数据源检查失败
+func (s *Scheduler) recordFailure(name string, err error) {
+	s.mu.Lock()
+	h := s.healthLocked(name)
+	before := h.state()
+	h.failures++
+	h.lastErrorAt = time.Now()
+	h.lastError = err.Error()
+	after := h.state()
+	failures := h.failures
+	s.mu.Unlock()
+
+	if after != before {
+		if after == HealthDown {
+			logger.Warnf("%s 连续失败 %d 次，熔断后每 %v 探测一次", name, failures, maxBackoff)
+		}
+		eventbus.Publish(eventbus.HealthChanged, eventbus.HealthEvent{Source: name, Health: string(after), Error: err.Error()})
+	}
+}
+
+// backoffDelayLocked 计算数据源因失败需要退避的间隔，没有失败时返回 0（调用方需持有 s.mu 读锁）
+func (s *Scheduler) backoffDelayLocked(name string, interval time.Duration) (time.Duration, string) {
+	h, ok := s.health[name]
+	if !ok || h.failures == 0 {
+		return 0, ""
+	}
+	return backoff(interval, h.failures), fmt.Sprintf("连续失败 %d 次，退避重试", h.failures)
+}
+
+// healthLocked 获取数据源的健康记录，不存在时创建（调用方需持有 s.mu 写锁）
+func (s *Scheduler) healthLocked(name string) *sourceHealth {
+	h, ok := s.health[name]
+	if !ok {
+		h = &sourceHealth{}
+		s.health[name] = h
+	}
+	return h
+}
+
+// HealthSummary 生成数据源健康状态摘要（用于托盘提示），如 "github: 正常, ld246: 异常"
+func HealthSummary(statuses []SourceStatus) string {
+	labels := map[HealthState]string{
+		HealthOK:       "正常",
+		HealthDegraded: "异常",
+		HealthDown:     "不可用",
+	}
+
+	summary := ""
+	for _, status := range statuses {
+		if !status.Enabled {
+			continue
+		}
+		if summary != "" {
+			summary += ", "
+		}
+		summary += status.Name + ": " + labels[status.Health]
+	}
+	return summary
+}
@@ -0,0 +1,247 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"notifyme/internal/eventbus"
+	"notifyme/internal/logger"
+	"notifyme/internal/monitor"
+	"notifyme/internal/store"
+	"notifyme/pkg/types"
+)
+
+// recentLimit GetRecentNotifications 返回的最大条数
+const recentLimit = 50
+
+// addNotifications 把通知写入历史数据库
+func (s *Scheduler) addNotifications(notifications []*types.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+
+	added, err := s.store.Put(notifications)
+	if err != nil {
+		logger.Warnf("保存通知失败: %v", err)
+		return
+	}
+
+	logger.Infof("添加 %d 条新通知到历史，更新 %d 条已存在的通知", added, len(notifications)-added)
+}
+
+// GetRecentNotifications 获取收件箱中最近的通知列表（不包含已归档和稍后提醒的通知）
+// 同一会话只保留最近一次更新
+func (s *Scheduler) GetRecentNotifications() []*types.Notification {
+	threads := s.GetRecentThreads()
+	notifications := make([]*types.Notification, 0, len(threads))
+	for _, thread := range threads {
+		notifications = append(notifications, thread.Latest)
+	}
+	return notifications
+}
+
+// GetRecentThreads 获取收件箱中最近有更新的会话（不包含已归档和稍后提醒的通知）
+func (s *Scheduler) GetRecentThreads() []*types.NotificationThread {
+	archived, snoozed := false, false
+	threads, err := s.store.Threads(types.NotificationQuery{Archived: &archived, Snoozed: &snoozed, Limit: recentLimit})
+	if err != nil {
+		logger.Errorf("读取最近通知失败: %v", err)
+		return []*types.NotificationThread{}
+	}
+	return threads
+}
+
+// GetNotification 按 ID 获取通知，不存在时返回 store.ErrNotFound
+func (s *Scheduler) GetNotification(id string) (*types.Notification, error) {
+	notification, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if notification == nil {
+		return nil, fmt.Errorf("%w: %s", store.ErrNotFound, id)
+	}
+	return notification, nil
+}
+
+// QueryNotifications 按条件查询通知历史
+func (s *Scheduler) QueryNotifications(query types.NotificationQuery) ([]*types.Notification, error) {
+	return s.store.List(query)
+}
+
+// CountNotifications 统计满足条件的通知条数
+func (s *Scheduler) CountNotifications(query types.NotificationQuery) (int, error) {
+	return s.store.Count(query)
+}
+
+// QueryThreads 按条件查询会话，Limit 和 Offset 按会话计数
+func (s *Scheduler) QueryThreads(query types.NotificationQuery) ([]*types.NotificationThread, error) {
+	return s.store.Threads(query)
+}
+
+// GetThread 获取会话中所有更新的时间线（按时间倒序）
+func (s *Scheduler) GetThread(key string) ([]*types.Notification, error) {
+	return s.store.Thread(key)
+}
+
+// MarkRead 把通知标记为已读，并同步到通知所在站点
+func (s *Scheduler) MarkRead(id string) error {
+	notification, err := s.setState(id, "标记已读", func(n *types.Notification) { n.Read = true })
+	if err != nil {
+		return err
+	}
+	s.syncRead(notification)
+	return nil
+}
+
+// MarkThreadRead 把会话中的所有更新标记为已读，并把最近一次更新同步到站点，返回标记的条数
+func (s *Scheduler) MarkThreadRead(key string) (int, error) {
+	count, err := s.store.MarkAllRead(func(n *types.Notification) bool {
+		return n.Thread() == key
+	})
+	if err != nil {
+		return 0, err
+	}
+	logger.Infof("已将会话 %s 的 %d 条更新标记为已读", key, count)
+	publishChanged(count)
+
+	if count > 0 {
+		if updates, err := s.store.Thread(key); err == nil && len(updates) > 0 {
+			s.syncRead(updates[0])
+		}
+	}
+	return count, nil
+}
+
+// MarkUnread 把通知标记为未读
+func (s *Scheduler) MarkUnread(id string) error {
+	_, err := s.setState(id, "标记未读", func(n *types.Notification) { n.Read = false })
+	return err
+}
+
+// MarkAllRead 把指定来源（为空表示全部来源）的通知全部标记为已读，并同步到站点，返回标记的条数
+func (s *Scheduler) MarkAllRead(source string) (int, error) {
+	count, err := s.store.MarkAllRead(func(n *types.Notification) bool {
+		return source == "" || n.Source == source
+	})
+	if err != nil {
+		return 0, err
+	}
+	logger.Infof("已将 %d 条通知标记为已读（来源: %s）", count, sourceLabel(source))
+	publishChanged(count)
+	s.syncAllRead(source)
+	return count, nil
+}
+
+// MarkRepoRead 把 GitHub 仓库（owner/repo）内的通知全部标记为已读，并同步到 GitHub，返回标记的条数
+func (s *Scheduler) MarkRepoRead(repo string) (int, error) {
+	count, err := s.store.MarkAllRead(func(n *types.Notification) bool {
+		return n.Source == "github" && n.Repository == repo
+	})
+	if err != nil {
+		return 0, err
+	}
+	logger.Infof("已将仓库 %s 的 %d 条通知标记为已读", repo, count)
+	publishChanged(count)
+	s.syncRepoRead(repo)
+	return count, nil
+}
+
+// MarkLd246CategoryRead 把 ld246 指定分类（commented, at, reply, comment2ed, following）的通知
+// 全部标记为已读，并同步到 ld246，返回标记的条数
+func (s *Scheduler) MarkLd246CategoryRead(category string) (int, error) {
+	count, err := s.store.MarkAllRead(func(n *types.Notification) bool {
+		return n.Source == "ld246" && monitor.Ld246CategoryOf(n) == category
+	})
+	if err != nil {
+		return 0, err
+	}
+	logger.Infof("已将 ld246 %s 分类的 %d 条通知标记为已读", category, count)
+	publishChanged(count)
+	s.syncCategoryRead(category)
+	return count, nil
+}
+
+// Archive 归档通知，归档的同时标记为已读，并在站点上标记为完成
+func (s *Scheduler) Archive(id string) error {
+	notification, err := s.setState(id, "归档", func(n *types.Notification) {
+		n.Archived = true
+		n.Read = true
+	})
+	if err != nil {
+		return err
+	}
+	s.syncDone(notification)
+	return nil
+}
+
+// Unarchive 把通知移回收件箱
+func (s *Scheduler) Unarchive(id string) error {
+	_, err := s.setState(id, "取消归档", func(n *types.Notification) { n.Archived = false })
+	return err
+}
+
+// Star 设置或取消通知的星标
+func (s *Scheduler) Star(id string, starred bool) error {
+	_, err := s.setState(id, "设置星标", func(n *types.Notification) { n.Starred = starred })
+	return err
+}
+
+// setState 修改通知的本地状态并保存，返回修改后的通知
+func (s *Scheduler) setState(id, action string, fn func(n *types.Notification)) (*types.Notification, error) {
+	var updated *types.Notification
+	err := s.store.Update(id, func(n *types.Notification) {
+		fn(n)
+		updated = n
+	})
+	if err != nil {
+		logger.Errorf("%s失败: %v", action, err)
+		return nil, err
+	}
+	logger.Debugf("%s: %s", action, id)
+	eventbus.Publish(eventbus.NotificationsChanged, []string{id})
+	return updated, nil
+}
+
+// publishChanged 批量修改通知状态后发布 NotificationsChanged 事件，没有修改时不发布
+func publishChanged(count int) {
+	if count > 0 {
+		eventbus.Publish(eventbus.NotificationsChanged, []string{})
+	}
+}
+
+// SetOpenAppHandler 设置点击汇总通知时打开应用主界面的回调
+func (s *Scheduler) SetOpenAppHandler(handler func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onOpenApp = handler
+}
+
+// sourceLabel 来源名称（为空时表示全部来源）
+func sourceLabel(source string) string {
+	if source == "" {
+		return "全部"
+	}
+	return source
+}
+
+// DataDir 获取数据目录（通知数据库、本机接口令牌等所在目录）
+func DataDir() string {
+	// 优先使用当前目录（与配置文件逻辑保持一致）
+	dataDir := filepath.Join(".", "data")
+	if _, err := os.Stat(dataDir); err == nil {
+		return dataDir
+	}
+
+	// 如果当前目录不存在，使用用户配置目录
+	homeDir, err := os.UserHomeDir()
+	if err == nil {
+		dataDir := filepath.Join(homeDir, ".notifyme", "data")
+		os.MkdirAll(dataDir, 0755)
+		return dataDir
+	}
+
+	// 如果无法获取用户目录，使用当前目录
+	os.MkdirAll(dataDir, 0755)
+	return dataDir
+}
@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/monitor"
+	"notifyme/pkg/types"
+)
+
+// mutedThreadRule 静音会话在规则处理结果中显示的名称
+const mutedThreadRule = "静音会话"
+
+// MuteThread 静音通知所在的会话：会话中已有的更新标记为已读，之后的更新只保存到历史，不再提醒
+// 站点支持时（GitHub）同时在站点上取消订阅该会话
+func (s *Scheduler) MuteThread(id string) error {
+	notification, err := s.GetNotification(id)
+	if err != nil {
+		return err
+	}
+
+	key := notification.Thread()
+	if err := s.store.MuteThread(key); err != nil {
+		return fmt.Errorf("静音会话失败: %w", err)
+	}
+	logger.Infof("已静音会话 %s", key)
+
+	if _, err := s.MarkThreadRead(key); err != nil {
+		logger.Warnf("把静音的会话标记为已读失败: %v", err)
+	}
+	s.syncMute(notification)
+	return nil
+}
+
+// UnmuteThread 取消静音通知所在的会话，之后的更新恢复提醒（不会恢复站点上的订阅）
+func (s *Scheduler) UnmuteThread(id string) error {
+	notification, err := s.GetNotification(id)
+	if err != nil {
+		return err
+	}
+
+	key := notification.Thread()
+	if err := s.store.UnmuteThread(key); err != nil {
+		return fmt.Errorf("取消静音会话失败: %w", err)
+	}
+	logger.Infof("已取消静音会话 %s", key)
+	return nil
+}
+
+// syncMute 在通知所在站点上取消订阅会话，站点不支持时只在本地静音
+func (s *Scheduler) syncMute(notification *types.Notification) {
+	s.syncRemote(notification.Source, "会话静音", func(ctx context.Context, src monitor.Source) error {
+		muter, ok := src.(monitor.ThreadMuter)
+		if !ok {
+			return nil
+		}
+		return muter.MuteThread(ctx, notification)
+	})
+}
@@ -0,0 +1,185 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/notifier"
+	"notifyme/internal/quiethours"
+	"notifyme/pkg/types"
+)
+
+// quietCheckInterval 检查勿扰状态是否结束的间隔
+const quietCheckInterval = time.Minute
+
+// DNDStatus 勿扰状态
+type DNDStatus struct {
+	Active      bool   `json:"active"`       // 当前是否处于勿扰状态
+	Reason      string `json:"reason"`       // 勿扰原因
+	PausedUntil int64  `json:"paused_until"` // 手动暂停的结束时间（Unix 秒），未暂停时为 0
+	Deferred    int    `json:"deferred"`     // 暂缓提醒的通知条数
+}
+
+// setQuietHours 设置勿扰时段
+func (s *Scheduler) setQuietHours(cfg types.QuietHoursConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setQuietHoursLocked(cfg)
+}
+
+// setQuietHoursLocked 设置勿扰时段，配置无效时不启用勿扰时段（调用方需持有 s.mu 写锁）
+func (s *Scheduler) setQuietHoursLocked(cfg types.QuietHoursConfig) {
+	schedule, err := quiethours.New(cfg)
+	if err != nil {
+		logger.Errorf("加载勿扰时段失败: %v", err)
+		schedule, _ = quiethours.New(types.QuietHoursConfig{})
+	}
+	s.quietHours = schedule
+}
+
+// PauseNotifications 手动暂停通知指定时长，期间收到的通知在恢复后汇总提醒
+func (s *Scheduler) PauseNotifications(duration time.Duration) {
+	s.mu.Lock()
+	s.dndUntil = time.Now().Add(duration)
+	until := s.dndUntil
+	s.mu.Unlock()
+
+	logger.Infof("已暂停通知到 %s", until.Format("2006-01-02 15:04"))
+}
+
+// ResumeNotifications 取消手动暂停，如果不在勿扰时段内，立即汇总提醒暂停期间收到的通知
+func (s *Scheduler) ResumeNotifications() {
+	s.mu.Lock()
+	s.dndUntil = time.Time{}
+	s.mu.Unlock()
+
+	logger.Info("已恢复通知")
+	if active, _ := s.doNotDisturb(); !active {
+		s.flushDeferred()
+	}
+}
+
+// DNDStatus 获取勿扰状态
+func (s *Scheduler) DNDStatus() DNDStatus {
+	var status DNDStatus
+	status.Active, status.Reason = s.doNotDisturb()
+
+	s.mu.RLock()
+	if time.Now().Before(s.dndUntil) {
+		status.PausedUntil = s.dndUntil.Unix()
+	}
+	s.mu.RUnlock()
+
+	if count, err := s.store.DeferredCount(); err == nil {
+		status.Deferred = count
+	}
+	return status
+}
+
+// doNotDisturb 检查当前是否处于勿扰状态（手动暂停或勿扰时段），返回原因
+func (s *Scheduler) doNotDisturb() (bool, string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	if now.Before(s.dndUntil) {
+		return true, fmt.Sprintf("已暂停通知到 %s", s.dndUntil.Format("15:04"))
+	}
+	if s.quietHours.Active(now) {
+		return true, "勿扰时段"
+	}
+	return false, ""
+}
+
+// runQuietHours 定期检查勿扰状态，勿扰结束后汇总提醒暂缓的通知
+func (s *Scheduler) runQuietHours() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(quietCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		// 启动时也检查一次，处理上次退出前暂缓的通知
+		if active, _ := s.doNotDisturb(); !active {
+			s.flushDeferred()
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// flushDeferred 把暂缓提醒的通知汇总成一条通知投递，至少一个渠道投递成功后才删除暂缓记录
+// 暂缓期间已经读过或归档的通知不再计入
+func (s *Scheduler) flushDeferred() {
+	s.deferredMu.Lock()
+	defer s.deferredMu.Unlock()
+
+	count, err := s.store.DeferredCount()
+	if err != nil || count == 0 {
+		return
+	}
+
+	deferred, err := s.store.Deferred()
+	if err != nil {
+		logger.Errorf("读取暂缓提醒的通知失败: %v", err)
+		return
+	}
+
+	pending := make([]*types.Notification, 0, len(deferred))
+	for _, notification := range deferred {
+		current, err := s.store.Get(notification.ID)
+		if err == nil && current != nil && (current.Read || current.Archived) {
+			continue
+		}
+		pending = append(pending, notification)
+	}
+	if len(pending) == 0 {
+		logger.Infof("勿扰期间的 %d 条通知都已处理，不再提醒", len(deferred))
+	} else {
+		summary := summarize(pending, fmt.Sprintf("勿扰期间收到 %d 条通知", len(pending)))
+		logger.Infof("勿扰结束，汇总提醒 %d 条通知", len(pending))
+		results := s.dispatcher.NotifyBatch([]*types.Notification{summary})
+		if len(succeeded([]*types.Notification{summary}, results)) == 0 {
+			// 保留暂缓记录，下次检查勿扰状态时重试
+			logger.Warn("汇总提醒勿扰期间的通知失败，稍后重试")
+			return
+		}
+	}
+
+	if err := s.store.ClearDeferred(deferred); err != nil {
+		logger.Errorf("删除暂缓记录失败: %v", err)
+	}
+}
+
+// summarize 生成一条汇总通知，内容为各来源的通知条数
+func summarize(notifications []*types.Notification, title string) *types.Notification {
+	counts := make(map[string]int)
+	var sources []string
+	for _, notification := range notifications {
+		if counts[notification.Source] == 0 {
+			sources = append(sources, notification.Source)
+		}
+		counts[notification.Source]++
+	}
+
+	parts := make([]string, 0, len(sources))
+	for _, source := range sources {
+		parts = append(parts, fmt.Sprintf("%s %d 条", sourceLabel(source), counts[source]))
+	}
+
+	now := time.Now()
+	return &types.Notification{
+		ID:      fmt.Sprintf("notifyme_summary_%d", now.UnixMilli()),
+		Title:   title,
+		Content: strings.Join(parts, "，"),
+		Link:    notifier.AppLink,
+		Source:  "notifyme",
+		Time:    now.UnixMilli(),
+	}
+}
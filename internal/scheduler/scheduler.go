@@ -2,55 +2,113 @@ package scheduler
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 	"sync"
 	"time"
 
+	"notifyme/internal/eventbus"
 	"notifyme/internal/logger"
 	"notifyme/internal/monitor"
 	"notifyme/internal/notifier"
+	"notifyme/internal/quiethours"
+	"notifyme/internal/rules"
+	"notifyme/internal/store"
 	"notifyme/pkg/types"
 )
 
+// deliveryRetention 投递记录保留时间，超过该时间的记录会在启动时清理
+const deliveryRetention = 90 * 24 * time.Hour
+
 // Scheduler 轮询调度器
 type Scheduler struct {
-	ld246Monitor  *monitor.Ld246Monitor
-	githubMonitor *monitor.GitHubMonitor
-	notifier      *notifier.WindowsNotifier
+	sources       map[string]monitor.Source // 数据源（名称 -> 数据源）
+	sourceNames   []string                  // 数据源名称（按注册顺序）
+	loops         map[string]*sourceLoop    // 正在运行的轮询循环（数据源名称 -> 循环）
+	health        map[string]*sourceHealth  // 数据源健康记录（数据源名称 -> 记录），重启循环时保留
+	dispatcher    *notifier.Dispatcher
 	config        *types.Config
 	ctx           context.Context
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
+	syncs         sync.WaitGroup // 正在后台同步到站点的操作
 	running       bool
 	mu            sync.RWMutex
-	// 最近的通知列表（最多 50 条）
-	recentNotifications []*types.Notification
-	notificationsMu     sync.RWMutex
+	store         *store.Store             // 通知历史数据库
+	ruleEngine    *rules.Engine            // 通知规则
+	quietHours    *quiethours.Schedule     // 勿扰时段
+	dndUntil      time.Time                // 手动暂停通知的结束时间
+	onOpenApp     func()                   // 点击汇总通知时打开应用主界面的回调
+	digestBuffers map[string]*digestBuffer // 合并窗口内等待汇总的通知（来源 + 渠道 -> 缓冲区）
+	digestMu      sync.Mutex               // 保护 digestBuffers 和 digestClosed
+	digestClosed  bool                     // 调度器已停止，不再使用合并窗口
+	digestFlushes sync.WaitGroup           // 合并窗口计时结束后正在进行的投递
+	deferredMu    sync.Mutex               // 保证同一时间只有一次暂缓提醒汇总
+}
+
+// sourceLoop 一个数据源的轮询循环
+type sourceLoop struct {
+	interval   time.Duration // 配置的轮询间隔
+	cancel     context.CancelFunc
+	nextPollAt time.Time // 下一次轮询时间（由 s.mu 保护）
+	reason     string    // 下一次轮询被推迟的原因，为空表示按配置间隔轮询（由 s.mu 保护）
+}
+
+// SourceStatus 数据源的运行状态
+type SourceStatus struct {
+	Name         string `json:"name"`
+	Enabled      bool   `json:"enabled"`       // 是否启用
+	Running      bool   `json:"running"`       // 轮询循环是否在运行
+	PollInterval int    `json:"poll_interval"` // 轮询间隔（秒）
+	NextPollAt   int64  `json:"next_poll_at"`  // 下一次轮询时间（Unix 秒），未运行时为 0
+	DelayReason  string `json:"delay_reason"`  // 下一次轮询被推迟的原因（如服务端限流），为空表示按配置间隔轮询
+
+	Health        HealthState `json:"health"`          // 健康状态
+	Failures      int         `json:"failures"`        // 连续失败次数
+	LastSuccessAt int64       `json:"last_success_at"` // 最近一次成功时间（Unix 秒）
+	LastErrorAt   int64       `json:"last_error_at"`   // 最近一次失败时间（Unix 秒）
+	LastError     string      `json:"last_error"`      // 最近一次失败原因
 }
 
 // NewScheduler 创建新的调度器
-func NewScheduler(cfg *types.Config) *Scheduler {
+func NewScheduler(cfg *types.Config) (*Scheduler, error) {
+	dataDir := DataDir()
+	db, err := store.Open(filepath.Join(dataDir, "notifications.db"))
+	if err != nil {
+		return nil, err
+	}
+
+	// 导入旧版本的通知列表文件（只会执行一次，导入后文件会被重命名）
+	if _, err := db.ImportJSON(filepath.Join(dataDir, "notifications.json")); err != nil {
+		logger.Warnf("导入旧版通知列表失败: %v", err)
+	}
+
+	// 清理过期的投递记录
+	if pruned, err := db.PruneDelivered(time.Now().Add(-deliveryRetention)); err != nil {
+		logger.Warnf("清理投递记录失败: %v", err)
+	} else if pruned > 0 {
+		logger.Infof("已清理 %d 条过期的投递记录", pruned)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	s := &Scheduler{
-		ld246Monitor:  monitor.NewLd246Monitor(cfg.Ld246.Token),
-		githubMonitor: monitor.NewGitHubMonitor(cfg.GitHub.Token),
-		notifier:      notifier.NewWindowsNotifier(),
+		loops:         make(map[string]*sourceLoop),
+		health:        make(map[string]*sourceHealth),
+		digestBuffers: make(map[string]*digestBuffer),
+		dispatcher:    notifier.NewDispatcher(cfg.Sinks),
 		config:        cfg,
 		ctx:           ctx,
 		cancel:        cancel,
 		running:       false,
+		store:         db,
 	}
+	s.setRules(cfg.Rules)
+	s.setQuietHours(cfg.QuietHours)
+	s.setSources(monitor.NewSources(cfg))
+	notifier.SetActionHandler(s.handleNotificationAction)
 
-	// 加载保存的通知列表
-	if err := s.loadNotifications(); err != nil {
-		logger.Warnf("加载通知列表失败: %v", err)
-	}
-
-	return s
+	return s, nil
 }
 
 // Start 启动调度器
@@ -61,32 +119,42 @@ func (s *Scheduler) Start() error {
 		return nil
 	}
 	s.running = true
-	s.mu.Unlock()
-
 	logger.Info("启动轮询调度器")
 
-	// 启动 ld246 监控
+	// 为每个数据源启动独立的轮询循环
+	s.syncLoopsLocked()
+	s.mu.Unlock()
+
+	// 勿扰结束后汇总提醒暂缓的通知
 	s.wg.Add(1)
-	go s.runLd246Monitor()
+	go s.runQuietHours()
 
-	// 启动 GitHub 监控
+	// 稍后提醒到期后重新提醒
 	s.wg.Add(1)
-	go s.runGitHubMonitor()
+	go s.runSnoozes()
 
 	return nil
 }
 
-// Stop 停止调度器
+// Stop 停止调度器并关闭通知数据库
+// 未启动过轮询（如命令行只执行一次检查）时同样需要调用，以便完成后台同步、投递合并窗口中的通知
 func (s *Scheduler) Stop() {
-	s.mu.Lock()
-	if !s.running {
-		s.mu.Unlock()
+	if s.ctx.Err() != nil {
 		return
 	}
+
+	s.mu.Lock()
+	running := s.running
 	s.running = false
+	s.loops = make(map[string]*sourceLoop)
 	s.mu.Unlock()
 
-	logger.Info("停止轮询调度器")
+	if running {
+		logger.Info("停止轮询调度器")
+	}
+
+	// 先等待正在进行的已读同步完成，避免退出时丢失
+	s.waitSyncs()
 	s.cancel()
 
 	// 使用带超时的等待，避免因为网络请求阻塞而无法退出
@@ -98,10 +166,18 @@ func (s *Scheduler) Stop() {
 
 	select {
 	case <-done:
-		logger.Info("调度器已完全停止")
+		if running {
+			logger.Info("调度器已完全停止")
+		}
 	case <-time.After(3 * time.Second):
 		logger.Warn("等待调度器停止超时，强制继续退出")
 	}
+
+	s.stopDigests()
+
+	if err := s.store.Close(); err != nil {
+		logger.Warnf("关闭通知数据库失败: %v", err)
+	}
 }
 
 // IsRunning 检查是否正在运行
@@ -112,313 +188,359 @@ func (s *Scheduler) IsRunning() bool {
 }
 
 // UpdateConfig 更新配置
+// 轮询间隔变化或启用状态变化的数据源会立即重启（或停止）各自的轮询循环，其他数据源不受影响
 func (s *Scheduler) UpdateConfig(cfg *types.Config) {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	s.config = cfg
-	s.ld246Monitor = monitor.NewLd246Monitor(cfg.Ld246.Token)
-	s.githubMonitor = monitor.NewGitHubMonitor(cfg.GitHub.Token)
-}
-
-// runLd246Monitor 运行 ld246 监控
-func (s *Scheduler) runLd246Monitor() {
-	defer s.wg.Done()
-
-	interval := time.Duration(s.config.PollInterval) * time.Second
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-
-	// 立即执行一次
-	s.checkLd246()
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			s.checkLd246()
-		}
+	s.setSourcesLocked(monitor.NewSources(cfg))
+	if s.running {
+		s.syncLoopsLocked()
 	}
-}
-
-// runGitHubMonitor 运行 GitHub 监控
-func (s *Scheduler) runGitHubMonitor() {
-	defer s.wg.Done()
+	s.dispatcher.Reconfigure(cfg.Sinks)
+	s.setRulesLocked(cfg.Rules)
+	s.setQuietHoursLocked(cfg.QuietHours)
+	s.mu.Unlock()
 
-	interval := time.Duration(s.config.PollInterval) * time.Second
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	eventbus.Publish(eventbus.ConfigReloaded, nil)
+}
 
-	// 立即执行一次
-	s.checkGitHub()
+// setSources 设置数据源列表
+func (s *Scheduler) setSources(sources []monitor.Source) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setSourcesLocked(sources)
+}
 
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			s.checkGitHub()
+// setSourcesLocked 设置数据源列表，配置中停用的数据源会被跳过（调用方需持有 s.mu 写锁）
+// 替换已有的数据源时保留旧实例的轮询建议
+func (s *Scheduler) setSourcesLocked(sources []monitor.Source) {
+	previous := s.sources
+	s.sources = make(map[string]monitor.Source, len(sources))
+	s.sourceNames = make([]string, 0, len(sources))
+	for _, src := range sources {
+		if !s.config.SourceEnabled(src.Name()) {
+			logger.Infof("数据源 %s 已停用", src.Name())
+			continue
 		}
+		s.restoreCursor(src)
+		carryPollHint(previous[src.Name()], src)
+		s.sources[src.Name()] = src
+		s.sourceNames = append(s.sourceNames, src.Name())
 	}
 }
 
-// checkLd246 检查 ld246 新消息
-func (s *Scheduler) checkLd246() {
-	logger.Debug("检查 ld246 新消息...")
-
-	// 获取最近回帖
-	replies, err := s.ld246Monitor.FetchRecentReplies()
-	if err != nil {
-		logger.Errorf("获取 ld246 最近回帖失败: %v", err)
-	} else {
-		if len(replies) > 0 {
-			logger.Infof("ld246: 获取到 %d 条最近回帖，准备发送和添加到列表", len(replies))
-			s.notifier.NotifyBatch(replies)
-			s.addNotifications(replies)
-		}
+// restoreCursor 从数据库恢复数据源的增量拉取位置
+func (s *Scheduler) restoreCursor(src monitor.Source) {
+	cursorer, ok := src.(monitor.Cursorer)
+	if !ok {
+		return
 	}
-
-	// 获取未读消息
-	messages, err := s.ld246Monitor.FetchUnreadMessages()
+	cursor, err := s.store.Cursor(src.Name())
 	if err != nil {
-		logger.Errorf("获取 ld246 未读消息失败: %v", err)
-	} else {
-		if len(messages) > 0 {
-			logger.Infof("ld246: 获取到 %d 条未读消息，准备发送和添加到列表", len(messages))
-			s.notifier.NotifyBatch(messages)
-			s.addNotifications(messages)
-		}
+		logger.Warnf("读取 %s 的拉取位置失败: %v", src.Name(), err)
+		return
 	}
-
-	logger.Info("ld246 检查完成")
-}
-
-// checkGitHub 检查 GitHub 新通知
-func (s *Scheduler) checkGitHub() {
-	logger.Debug("检查 GitHub 新通知...")
-
-	notifications, err := s.githubMonitor.FetchNotifications()
-	if err != nil {
-		logger.Errorf("获取 GitHub 通知失败: %v", err)
-		logger.Info("GitHub 检查完成")
+	if err := cursorer.SetCursor(cursor); err != nil {
+		logger.Warnf("恢复 %s 的拉取位置失败: %v", src.Name(), err)
 		return
 	}
-
-	if len(notifications) > 0 {
-		logger.Infof("GitHub: 获取到 %d 条通知，准备发送和添加到列表", len(notifications))
-		s.notifier.NotifyBatch(notifications)
-		s.addNotifications(notifications)
+	if cursor != "" {
+		logger.Debugf("已恢复 %s 的拉取位置: %s", src.Name(), cursor)
 	}
-
-	logger.Info("GitHub 检查完成")
 }
 
-// addNotifications 添加通知到最近通知列表（插入到顶部，最多保留 50 条）
-// 如果通知已存在，会将其移动到列表最前面
-func (s *Scheduler) addNotifications(notifications []*types.Notification) {
-	if len(notifications) == 0 {
+// carryPollHint 把旧数据源实例的轮询建议（最小轮询间隔、暂停请求的时间）交给新实例
+func carryPollHint(old, src monitor.Source) {
+	oldHinter, ok := old.(monitor.PollHinter)
+	if !ok {
 		return
 	}
-
-	s.notificationsMu.Lock()
-
-	// 创建已存在通知 ID 的集合，用于去重和查找
-	existingIDs := make(map[string]bool)
-	for _, notif := range s.recentNotifications {
-		existingIDs[notif.ID] = true
+	hinter, ok := src.(monitor.PollHinter)
+	if !ok {
+		return
 	}
+	hinter.SetPollHint(oldHinter.PollHint())
+}
 
-	// 分离新通知和已存在的通知
-	newNotifications := make([]*types.Notification, 0, len(notifications))
-	existingNotifications := make([]*types.Notification, 0, len(notifications))
-	seenIDs := make(map[string]bool) // 用于同一批次内去重
-
-	for _, notif := range notifications {
-		// 同一批次内去重
-		if seenIDs[notif.ID] {
-			continue
-		}
-		seenIDs[notif.ID] = true
-
-		if existingIDs[notif.ID] {
-			// 已存在的通知，需要移动到最前面
-			existingNotifications = append(existingNotifications, notif)
-		} else {
-			// 新通知
-			newNotifications = append(newNotifications, notif)
-		}
+// saveCursor 持久化数据源的增量拉取位置
+func (s *Scheduler) saveCursor(src monitor.Source) {
+	cursorer, ok := src.(monitor.Cursorer)
+	if !ok {
+		return
 	}
-
-	// 如果既没有新通知也没有需要移动的通知，直接返回
-	if len(newNotifications) == 0 && len(existingNotifications) == 0 {
-		s.notificationsMu.Unlock()
-		logger.Debug("所有通知都已存在于列表中，跳过添加")
+	cursor := cursorer.Cursor()
+	if cursor == "" {
 		return
 	}
+	if err := s.store.SetCursor(src.Name(), cursor); err != nil {
+		logger.Warnf("保存 %s 的拉取位置失败: %v", src.Name(), err)
+	}
+}
 
-	// 移除已存在的通知（从原位置删除）
-	if len(existingNotifications) > 0 {
-		existingIDsToRemove := make(map[string]bool)
-		for _, notif := range existingNotifications {
-			existingIDsToRemove[notif.ID] = true
+// syncLoopsLocked 让正在运行的轮询循环与当前数据源和配置保持一致（调用方需持有 s.mu 写锁）
+// 已移除的数据源停止循环，轮询间隔变化的数据源重启循环，新增的数据源启动循环
+func (s *Scheduler) syncLoopsLocked() {
+	for name, loop := range s.loops {
+		if _, ok := s.sources[name]; !ok {
+			loop.cancel()
+			delete(s.loops, name)
+			logger.Infof("已停止 %s 的轮询", name)
 		}
+	}
 
-		// 过滤掉需要移动的通知
-		filteredNotifications := make([]*types.Notification, 0, len(s.recentNotifications))
-		for _, notif := range s.recentNotifications {
-			if !existingIDsToRemove[notif.ID] {
-				filteredNotifications = append(filteredNotifications, notif)
+	for _, name := range s.sourceNames {
+		interval := time.Duration(s.config.SourcePollInterval(name)) * time.Second
+		if loop, ok := s.loops[name]; ok {
+			if loop.interval == interval {
+				continue
 			}
+			loop.cancel()
+			logger.Infof("%s 的轮询间隔变为 %v，重启轮询", name, interval)
 		}
-		s.recentNotifications = filteredNotifications
 
-		logger.Debugf("从列表中移除 %d 条已存在的通知，准备移动到最前面", len(existingNotifications))
+		ctx, cancel := context.WithCancel(s.ctx)
+		loop := &sourceLoop{interval: interval, cancel: cancel}
+		s.loops[name] = loop
+		s.wg.Add(1)
+		go s.runSource(ctx, name, loop)
 	}
+}
 
-	// 将需要移动的通知和新通知都插入到列表顶部（先插入需要移动的，再插入新的）
-	notificationsToAdd := append(existingNotifications, newNotifications...)
-	s.recentNotifications = append(notificationsToAdd, s.recentNotifications...)
+// SourceNames 获取当前数据源名称列表
+func (s *Scheduler) SourceNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	// 如果超过 50 条，删除末尾的
-	if len(s.recentNotifications) > 50 {
-		s.recentNotifications = s.recentNotifications[:50]
-	}
+	result := make([]string, len(s.sourceNames))
+	copy(result, s.sourceNames)
+	return result
+}
 
-	logger.Infof("添加 %d 条新通知到列表，移动 %d 条已存在的通知到最前面（共处理 %d 条通知）",
-		len(newNotifications), len(existingNotifications), len(notifications))
+// getSource 按名称获取当前的数据源
+// 每次检查时重新获取，这样 UpdateConfig 替换的数据源能在下一轮生效
+func (s *Scheduler) getSource(name string) monitor.Source {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sources[name]
+}
 
-	// 复制数据用于保存（在释放锁之前）
-	notificationsToSave := make([]*types.Notification, len(s.recentNotifications))
-	copy(notificationsToSave, s.recentNotifications)
+// runSource 运行指定数据源的轮询循环，ctx 取消时退出
+// 每次检查后根据数据源的轮询建议计算下一次轮询时间
+func (s *Scheduler) runSource(ctx context.Context, name string, loop *sourceLoop) {
+	defer s.wg.Done()
 
-	s.notificationsMu.Unlock()
+	// 立即执行一次
+	s.checkSource(ctx, name)
 
-	// 保存到文件（在锁外执行，避免死锁）
-	if err := s.saveNotificationsWithData(notificationsToSave); err != nil {
-		logger.Warnf("保存通知列表失败: %v", err)
-	} else {
-		logger.Infof("成功保存 %d 条通知到文件", len(notificationsToSave))
-	}
-}
+	for {
+		delay, reason := s.nextDelay(name, loop.interval)
 
-// GetRecentNotifications 获取最近的通知列表
-func (s *Scheduler) GetRecentNotifications() []*types.Notification {
-	s.notificationsMu.RLock()
-	defer s.notificationsMu.RUnlock()
+		s.mu.Lock()
+		loop.nextPollAt = time.Now().Add(delay)
+		loop.reason = reason
+		s.mu.Unlock()
 
-	// 返回副本，避免外部修改
-	result := make([]*types.Notification, len(s.recentNotifications))
-	copy(result, s.recentNotifications)
-	return result
+		if reason != "" {
+			logger.Infof("%s 下一次轮询推迟到 %s: %s", name, loop.nextPollAt.Format("15:04:05"), reason)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.checkSource(ctx, name)
+		}
+	}
 }
 
-// TriggerCheck 手动触发检查（立即检查所有监控源）
-func (s *Scheduler) TriggerCheck() {
+// nextDelay 计算数据源距下一次轮询的时间，以及推迟轮询的原因（按配置间隔轮询时为空）
+// 取失败退避间隔和服务端轮询建议中最晚的一个
+func (s *Scheduler) nextDelay(name string, interval time.Duration) (time.Duration, string) {
+	delay, reason := interval, ""
+
 	s.mu.RLock()
-	running := s.running
+	if backoffDelay, backoffReason := s.backoffDelayLocked(name, interval); backoffDelay > 0 {
+		delay, reason = backoffDelay, backoffReason
+	}
 	s.mu.RUnlock()
 
-	if !running {
-		logger.Warn("调度器未运行，无法触发检查")
-		return
+	hinter, ok := s.getSource(name).(monitor.PollHinter)
+	if !ok {
+		return delay, reason
 	}
 
-	logger.Info("手动触发检查...")
-
-	// 在 goroutine 中执行检查，避免阻塞
-	go func() {
-		s.checkLd246()
-		s.checkGitHub()
-	}()
+	hint := hinter.PollHint()
+	if hint.MinInterval > delay {
+		delay = hint.MinInterval
+		reason = fmt.Sprintf("服务端要求轮询间隔不小于 %v", hint.MinInterval)
+	}
+	if wait := time.Until(hint.NotBefore); wait > delay {
+		delay = wait
+		reason = hint.Reason
+	}
+	return delay, reason
 }
 
-// getNotificationsFilePath 获取通知列表文件路径
-func (s *Scheduler) getNotificationsFilePath() string {
-	// 优先使用当前目录（与配置文件逻辑保持一致）
-	dataDir := filepath.Join(".", "data")
-	if _, err := os.Stat(dataDir); err == nil {
-		return filepath.Join(dataDir, "notifications.json")
+// pausedUntil 检查数据源是否被服务端要求暂停请求，返回恢复时间和原因
+func (s *Scheduler) pausedUntil(name string) (time.Time, string) {
+	hinter, ok := s.getSource(name).(monitor.PollHinter)
+	if !ok {
+		return time.Time{}, ""
 	}
-
-	// 如果当前目录不存在，使用用户配置目录
-	homeDir, err := os.UserHomeDir()
-	if err == nil {
-		dataDir := filepath.Join(homeDir, ".notifyme", "data")
-		os.MkdirAll(dataDir, 0755)
-		return filepath.Join(dataDir, "notifications.json")
+	hint := hinter.PollHint()
+	if time.Now().Before(hint.NotBefore) {
+		return hint.NotBefore, hint.Reason
 	}
+	return time.Time{}, ""
+}
 
-	// 如果无法获取用户目录，使用当前目录（即使不存在也会在保存时创建）
-	return filepath.Join(dataDir, "notifications.json")
+// CheckResult 一个数据源单次检查的结果
+type CheckResult struct {
+	Source        string                `json:"source"`
+	Notifications []*types.Notification `json:"notifications"`   // 拉取到并保留（未被规则丢弃）的通知
+	Error         string                `json:"error,omitempty"` // 为空表示检查成功
 }
 
-// saveNotifications 保存通知列表到文件（从当前列表读取）
-func (s *Scheduler) saveNotifications() error {
-	s.notificationsMu.RLock()
-	notifications := make([]*types.Notification, len(s.recentNotifications))
-	copy(notifications, s.recentNotifications)
-	s.notificationsMu.RUnlock()
+// CheckSources 立即检查指定数据源（为空表示所有启用的数据源）一次并等待完成，
+// 新通知与定时轮询一样执行规则、投递并保存到通知历史
+func (s *Scheduler) CheckSources(ctx context.Context, names []string) []CheckResult {
+	if len(names) == 0 {
+		names = s.SourceNames()
+	}
 
-	return s.saveNotificationsWithData(notifications)
+	results := make([]CheckResult, 0, len(names))
+	for _, name := range names {
+		result := CheckResult{Source: name}
+		notifications, err := s.checkSource(ctx, name)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if notifications == nil {
+			notifications = []*types.Notification{}
+		}
+		result.Notifications = notifications
+		results = append(results, result)
+	}
+	return results
 }
 
-// saveNotificationsWithData 保存指定的通知列表到文件
-func (s *Scheduler) saveNotificationsWithData(notifications []*types.Notification) error {
-	filePath := s.getNotificationsFilePath()
-	dataDir := filepath.Dir(filePath)
-
-	// 确保目录存在
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %w", err)
+// checkSource 检查指定数据源的新通知，返回未被规则丢弃的通知
+// 检查开始和结束时发布 CheckStarted、CheckFinished 事件
+func (s *Scheduler) checkSource(ctx context.Context, name string) (notifications []*types.Notification, err error) {
+	src := s.getSource(name)
+	if src == nil {
+		logger.Warnf("数据源 %s 不存在，跳过检查", name)
+		return nil, fmt.Errorf("数据源 %s 不存在或未启用", name)
 	}
 
-	// 序列化为 JSON
-	data, err := json.MarshalIndent(notifications, "", "  ")
+	logger.Debugf("检查 %s 新通知...", name)
+	eventbus.Publish(eventbus.CheckStarted, eventbus.CheckEvent{Source: name})
+	var pending []*types.Notification
+	defer func() {
+		finished := eventbus.CheckEvent{Source: name, New: len(pending)}
+		if err != nil {
+			finished.Error = err.Error()
+		}
+		eventbus.Publish(eventbus.CheckFinished, finished)
+	}()
+
+	notifications, err = src.Fetch(ctx)
 	if err != nil {
-		return fmt.Errorf("序列化通知列表失败: %w", err)
+		if ctx.Err() != nil {
+			// 循环被停止或重启，不计入失败
+			return nil, ctx.Err()
+		}
+		logger.Errorf("获取 %s 通知失败: %v", name, err)
+		s.recordFailure(name, err)
+		logger.Infof("%s 检查完成", name)
+		return nil, err
 	}
+	s.recordSuccess(name)
 
-	// 写入文件
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("写入文件失败: %w", err)
+	if len(notifications) > 0 {
+		logger.Infof("%s: 获取到 %d 条通知，准备发送和添加到列表", name, len(notifications))
+		var decisions map[string]rules.Decision
+		notifications, decisions = s.applyRules(notifications)
+		pending = s.deliver(notifications, decisions)
+		s.addNotifications(notifications)
+		if len(pending) > 0 {
+			eventbus.Publish(eventbus.NotificationsNew, pending)
+		}
 	}
+	// 通知入库后再保存拉取位置，避免崩溃时丢失通知
+	s.saveCursor(src)
 
-	logger.Infof("通知列表已保存到文件: %s (共 %d 条)", filePath, len(notifications))
-	return nil
+	logger.Infof("%s 检查完成", name)
+	return notifications, nil
 }
 
-// loadNotifications 从文件加载通知列表
-func (s *Scheduler) loadNotifications() error {
-	filePath := s.getNotificationsFilePath()
+// SinkStatus 获取各通知渠道的投递统计
+func (s *Scheduler) SinkStatus() []notifier.SinkStatus {
+	return s.dispatcher.Status()
+}
 
-	// 检查文件是否存在
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		logger.Debug("通知列表文件不存在，跳过加载")
-		return nil
-	}
+// SourceStatus 获取所有已注册数据源的运行状态（按注册顺序）
+func (s *Scheduler) SourceStatus() []SourceStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	// 读取文件
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		return fmt.Errorf("读取文件失败: %w", err)
-	}
+	names := monitor.Registered()
+	result := make([]SourceStatus, 0, len(names))
+	for _, name := range names {
+		status := SourceStatus{
+			Name:         name,
+			Enabled:      s.config.SourceEnabled(name),
+			PollInterval: s.config.SourcePollInterval(name),
+		}
+		if loop, ok := s.loops[name]; ok {
+			status.Running = true
+			if !loop.nextPollAt.IsZero() {
+				status.NextPollAt = loop.nextPollAt.Unix()
+			}
+			status.DelayReason = loop.reason
+		}
 
-	// 反序列化
-	var notifications []*types.Notification
-	if err := json.Unmarshal(data, &notifications); err != nil {
-		return fmt.Errorf("解析通知列表失败: %w", err)
+		status.Health = HealthOK
+		if h, ok := s.health[name]; ok {
+			status.Health = h.state()
+			status.Failures = h.failures
+			status.LastError = h.lastError
+			if !h.lastSuccessAt.IsZero() {
+				status.LastSuccessAt = h.lastSuccessAt.Unix()
+			}
+			if !h.lastErrorAt.IsZero() {
+				status.LastErrorAt = h.lastErrorAt.Unix()
+			}
+		}
+		result = append(result, status)
 	}
+	return result
+}
+
+// TriggerCheck 手动触发检查（立即检查所有监控源）
+func (s *Scheduler) TriggerCheck() {
+	s.mu.RLock()
+	running := s.running
+	s.mu.RUnlock()
 
-	// 限制最多 50 条
-	if len(notifications) > 50 {
-		notifications = notifications[:50]
+	if !running {
+		logger.Warn("调度器未运行，无法触发检查")
+		return
 	}
 
-	// 更新通知列表
-	s.notificationsMu.Lock()
-	s.recentNotifications = notifications
-	s.notificationsMu.Unlock()
+	logger.Info("手动触发检查...")
 
-	logger.Infof("成功加载 %d 条通知", len(notifications))
-	return nil
+	// 在 goroutine 中执行检查，避免阻塞
+	go func() {
+		for _, name := range s.SourceNames() {
+			// 服务端要求暂停的数据源（如配额耗尽）不参与手动检查
+			if until, reason := s.pausedUntil(name); !until.IsZero() {
+				logger.Infof("%s 暂停请求到 %s（%s），跳过手动检查", name, until.Format("15:04:05"), reason)
+				continue
+			}
+			s.checkSource(s.ctx, name)
+		}
+	}()
 }
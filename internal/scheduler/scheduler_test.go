@@ -0,0 +1,290 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"notifyme/internal/monitor"
+	"notifyme/internal/notifier"
+	"notifyme/internal/rules"
+	"notifyme/pkg/types"
+)
+
+// fakeSink 记录投递的通知，fail 为 true 时投递失败
+type fakeSink struct {
+	mu        sync.Mutex
+	fail      bool
+	delivered []string
+}
+
+func (f *fakeSink) Notify(notification *types.Notification) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail {
+		return errors.New("投递失败")
+	}
+	f.delivered = append(f.delivered, notification.ID)
+	return nil
+}
+
+func (f *fakeSink) setFail(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail = fail
+}
+
+// ids 已投递的通知 ID
+func (f *fakeSink) ids() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.delivered...)
+}
+
+// testSinks "test" 类型渠道的实例（渠道名称 -> 实例），创建调度器时由渠道工厂写入
+var testSinks = make(map[string]*fakeSink)
+
+func init() {
+	notifier.RegisterSink("test", func(cfg types.SinkConfig) (notifier.Sink, error) {
+		sink := &fakeSink{}
+		testSinks[cfg.Name] = sink
+		return sink, nil
+	})
+}
+
+// fakeSource 每次拉取都返回同一组通知（的副本）
+type fakeSource struct {
+	notifications []types.Notification
+}
+
+func (f *fakeSource) Name() string { return "fake" }
+
+func (f *fakeSource) Fetch(ctx context.Context) ([]*types.Notification, error) {
+	result := make([]*types.Notification, 0, len(f.notifications))
+	for _, notification := range f.notifications {
+		copied := notification
+		result = append(result, &copied)
+	}
+	return result, nil
+}
+
+func (f *fakeSource) Capabilities() []monitor.Capability { return nil }
+
+// testConfig 只有一个名为 main 的测试渠道
+func testConfig() *types.Config {
+	return &types.Config{
+		PollInterval: 60,
+		Sinks:        []types.SinkConfig{{Name: "main", Type: "test", Enabled: true}},
+	}
+}
+
+// testNotifications 测试数据源返回的通知
+func testNotifications() []types.Notification {
+	return []types.Notification{
+		{ID: "fake_1", Source: "fake", Title: "one", Time: 1700000100000},
+		{ID: "fake_2", Source: "fake", Title: "two", Time: 1700000200000},
+	}
+}
+
+// openTestScheduler 在 dir 下的 data 目录中创建调度器，数据源替换为 src，测试结束时停止
+// 调度器从当前目录的 data 目录打开数据库，调用前需要切换到 dir
+func openTestScheduler(t *testing.T, dir string, cfg *types.Config, src monitor.Source) *Scheduler {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewScheduler(cfg)
+	if err != nil {
+		t.Fatalf("NewScheduler 失败: %v", err)
+	}
+	s.setSources([]monitor.Source{src})
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// check 检查一次测试数据源
+func check(t *testing.T, s *Scheduler) {
+	t.Helper()
+	if _, err := s.checkSource(context.Background(), "fake"); err != nil {
+		t.Fatalf("checkSource 失败: %v", err)
+	}
+}
+
+// undelivered 测试数据源的通知中尚未写入投递记录的通知 ID
+func undelivered(t *testing.T, s *Scheduler, src *fakeSource) []string {
+	t.Helper()
+	notifications, _ := src.Fetch(context.Background())
+	pending, err := s.store.Undelivered(notifications)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make([]string, 0, len(pending))
+	for _, notification := range pending {
+		result = append(result, notification.ID)
+	}
+	return result
+}
+
+func TestDeliverOnceAcrossReopen(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	src := &fakeSource{notifications: testNotifications()}
+
+	s := openTestScheduler(t, dir, testConfig(), src)
+	check(t, s)
+	check(t, s)
+	if got, want := testSinks["main"].ids(), []string{"fake_1", "fake_2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("投递 = %v, want %v", got, want)
+	}
+	s.Stop()
+
+	// 重新打开后同样的通知不再投递，时间更新的通知重新投递
+	s = openTestScheduler(t, dir, testConfig(), src)
+	src.notifications[1].Time = 1700000300000
+	check(t, s)
+	if got, want := testSinks["main"].ids(), []string{"fake_2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("重新打开后投递 = %v, want %v", got, want)
+	}
+}
+
+func TestFailedSinkLeavesUndelivered(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	src := &fakeSource{notifications: testNotifications()}
+
+	s := openTestScheduler(t, dir, testConfig(), src)
+	sink := testSinks["main"]
+	sink.setFail(true)
+	check(t, s)
+	if got := undelivered(t, s, src); len(got) != 2 {
+		t.Fatalf("投递失败后未投递的通知 = %v, want 全部", got)
+	}
+
+	// 渠道恢复后再次拉取到时重试
+	sink.setFail(false)
+	check(t, s)
+	if got, want := sink.ids(), []string{"fake_1", "fake_2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("重试投递 = %v, want %v", got, want)
+	}
+	if got := undelivered(t, s, src); len(got) != 0 {
+		t.Errorf("重试后未投递的通知 = %v, want 空", got)
+	}
+}
+
+func TestRouteToUnavailableSink(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	src := &fakeSource{notifications: testNotifications()}
+
+	cfg := testConfig()
+	cfg.Sinks = append(cfg.Sinks, types.SinkConfig{Name: "off", Type: "test"})
+	cfg.Rules = []types.Rule{{Name: "route", Enabled: true, Match: types.RuleMatch{Title: "two"}, Action: rules.ActionRoute, Sinks: []string{"off"}}}
+
+	s := openTestScheduler(t, dir, cfg, src)
+	check(t, s)
+	if got, want := testSinks["main"].ids(), []string{"fake_1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("投递 = %v, want %v", got, want)
+	}
+	// 路由到未启用渠道的通知不记为已投递
+	if got, want := undelivered(t, s, src), []string{"fake_2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("未投递的通知 = %v, want %v", got, want)
+	}
+}
+
+func TestDigestFlushMarksDelivered(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	src := &fakeSource{notifications: testNotifications()}
+
+	cfg := testConfig()
+	cfg.Digest = types.DigestConfig{Enabled: true, Window: 1}
+	s := openTestScheduler(t, dir, cfg, src)
+	sink := testSinks["main"]
+
+	// 窗口内的通知先缓冲，不写入投递记录；再次拉取到的同一条通知不重复计入
+	check(t, s)
+	check(t, s)
+	if got := sink.ids(); len(got) != 0 {
+		t.Fatalf("窗口结束前投递 = %v, want 空", got)
+	}
+	if got := undelivered(t, s, src); len(got) != 2 {
+		t.Fatalf("窗口结束前未投递的通知 = %v, want 全部", got)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sink.ids()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if got := sink.ids(); len(got) != 1 {
+		t.Fatalf("窗口结束后投递 = %v, want 一条汇总", got)
+	}
+	if got := undelivered(t, s, src); len(got) != 0 {
+		t.Errorf("窗口结束后未投递的通知 = %v, want 空", got)
+	}
+}
+
+func TestStopFlushesDigest(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	src := &fakeSource{notifications: testNotifications()}
+
+	cfg := testConfig()
+	cfg.Digest = types.DigestConfig{Enabled: true, Window: 3600}
+	s := openTestScheduler(t, dir, cfg, src)
+	check(t, s)
+
+	// 停止时立即投递窗口中的通知，并在关闭数据库前写入投递记录
+	s.Stop()
+	if got := testSinks["main"].ids(); len(got) != 1 {
+		t.Fatalf("停止时投递 = %v, want 一条汇总", got)
+	}
+	s = openTestScheduler(t, dir, cfg, src)
+	if got := undelivered(t, s, src); len(got) != 0 {
+		t.Errorf("重新打开后未投递的通知 = %v, want 空", got)
+	}
+}
+
+func TestQuietHoursDeferAndFlush(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	src := &fakeSource{notifications: testNotifications()}
+
+	s := openTestScheduler(t, dir, testConfig(), src)
+	sink := testSinks["main"]
+
+	s.PauseNotifications(time.Hour)
+	check(t, s)
+	if got := sink.ids(); len(got) != 0 {
+		t.Fatalf("暂停期间投递 = %v, want 空", got)
+	}
+	if status := s.DNDStatus(); !status.Active || status.Deferred != 2 {
+		t.Fatalf("DNDStatus = %+v, want 勿扰中且暂缓 2 条", status)
+	}
+
+	// 汇总投递失败时保留暂缓记录
+	sink.setFail(true)
+	s.ResumeNotifications()
+	if status := s.DNDStatus(); status.Deferred != 2 {
+		t.Fatalf("汇总失败后暂缓 %d 条, want 2", status.Deferred)
+	}
+
+	sink.setFail(false)
+	s.ResumeNotifications()
+	if got := sink.ids(); len(got) != 1 {
+		t.Fatalf("恢复后投递 = %v, want 一条汇总", got)
+	}
+	if status := s.DNDStatus(); status.Deferred != 0 {
+		t.Errorf("汇总后暂缓 %d 条, want 0", status.Deferred)
+	}
+
+	// 暂缓的通知已记为已投递，之后再次拉取到时不会重复提醒
+	check(t, s)
+	if got := sink.ids(); len(got) != 1 {
+		t.Errorf("再次拉取后投递 = %v, want 只有汇总", got)
+	}
+}
@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/pkg/types"
+)
+
+// snoozeCheckInterval 检查稍后提醒是否到期的间隔
+const snoozeCheckInterval = 30 * time.Second
+
+// Snooze 设置稍后提醒：通知从收件箱中隐藏，到 until 时重新提醒并标记为未读
+func (s *Scheduler) Snooze(id string, until time.Time) error {
+	if !until.After(time.Now()) {
+		return fmt.Errorf("提醒时间必须晚于当前时间")
+	}
+	_, err := s.setState(id, "设置稍后提醒", func(n *types.Notification) { n.SnoozedUntil = until.UnixMilli() })
+	if err != nil {
+		return err
+	}
+	logger.Infof("通知 %s 将于 %s 重新提醒", id, until.Format("2006-01-02 15:04"))
+	return nil
+}
+
+// CancelSnooze 取消稍后提醒，通知回到收件箱
+func (s *Scheduler) CancelSnooze(id string) error {
+	_, err := s.setState(id, "取消稍后提醒", func(n *types.Notification) { n.SnoozedUntil = 0 })
+	return err
+}
+
+// runSnoozes 定期检查稍后提醒是否到期，到期的通知重新提醒
+func (s *Scheduler) runSnoozes() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(snoozeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		// 启动时也检查一次，处理退出期间到期的提醒
+		s.wakeSnoozed()
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// wakeSnoozed 重新提醒到期的通知：清除稍后提醒、标记为未读并移回收件箱，然后按规则投递
+func (s *Scheduler) wakeSnoozed() {
+	due, err := s.store.DueSnoozed(time.Now().UnixMilli())
+	if err != nil {
+		logger.Errorf("读取到期的稍后提醒失败: %v", err)
+		return
+	}
+
+	reminders := make([]*types.Notification, 0, len(due))
+	for _, notification := range due {
+		woken, err := s.setState(notification.ID, "稍后提醒到期", func(n *types.Notification) {
+			n.SnoozedUntil = 0
+			n.Read = false
+			n.Archived = false
+		})
+		if err != nil {
+			continue
+		}
+
+		reminder := *woken
+		reminder.Title = "稍后提醒：" + reminder.Title
+		reminders = append(reminders, &reminder)
+	}
+	if len(reminders) == 0 {
+		return
+	}
+
+	logger.Infof("%d 条稍后提醒到期，重新提醒", len(reminders))
+	reminders, decisions := s.applyRules(reminders)
+	s.dispatch(reminders, decisions)
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/internal/monitor"
+	"notifyme/pkg/types"
+)
+
+// syncTimeout 同步单个操作到站点的超时时间
+const syncTimeout = 30 * time.Second
+
+// repoReadMarker 支持按仓库标记已读的数据源（GitHub）
+type repoReadMarker interface {
+	MarkRepoRead(ctx context.Context, repo string) error
+}
+
+// categoryReadMarker 支持按分类标记已读的数据源（ld246）
+type categoryReadMarker interface {
+	MarkCategoryRead(ctx context.Context, category string) error
+}
+
+// syncRead 把通知的已读状态同步到所在站点
+func (s *Scheduler) syncRead(notification *types.Notification) {
+	s.syncRemote(notification.Source, "已读状态", func(ctx context.Context, src monitor.Source) error {
+		marker, ok := src.(monitor.ReadMarker)
+		if !ok {
+			return nil
+		}
+		return marker.MarkRead(ctx, notification)
+	})
+}
+
+// syncDone 把通知的完成状态同步到所在站点，站点不支持完成时退化为已读
+func (s *Scheduler) syncDone(notification *types.Notification) {
+	s.syncRemote(notification.Source, "完成状态", func(ctx context.Context, src monitor.Source) error {
+		if marker, ok := src.(monitor.DoneMarker); ok {
+			return marker.MarkDone(ctx, notification)
+		}
+		if marker, ok := src.(monitor.ReadMarker); ok {
+			return marker.MarkRead(ctx, notification)
+		}
+		return nil
+	})
+}
+
+// syncAllRead 把“全部已读”同步到指定站点（为空表示全部站点）
+func (s *Scheduler) syncAllRead(source string) {
+	for _, name := range s.SourceNames() {
+		if source != "" && name != source {
+			continue
+		}
+		s.syncRemote(name, "全部已读", func(ctx context.Context, src monitor.Source) error {
+			marker, ok := src.(monitor.AllReadMarker)
+			if !ok {
+				return nil
+			}
+			return marker.MarkAllRead(ctx)
+		})
+	}
+}
+
+// syncRepoRead 把仓库内通知的已读状态同步到 GitHub
+func (s *Scheduler) syncRepoRead(repo string) {
+	s.syncRemote("github", "仓库已读状态", func(ctx context.Context, src monitor.Source) error {
+		marker, ok := src.(repoReadMarker)
+		if !ok {
+			return nil
+		}
+		return marker.MarkRepoRead(ctx, repo)
+	})
+}
+
+// syncCategoryRead 把分类消息的已读状态同步到 ld246
+func (s *Scheduler) syncCategoryRead(category string) {
+	s.syncRemote("ld246", "分类已读状态", func(ctx context.Context, src monitor.Source) error {
+		marker, ok := src.(categoryReadMarker)
+		if !ok {
+			return nil
+		}
+		return marker.MarkCategoryRead(ctx, category)
+	})
+}
+
+// syncRemote 在后台执行同步操作，失败只记录日志，不影响本地状态
+func (s *Scheduler) syncRemote(sourceName, what string, fn func(ctx context.Context, src monitor.Source) error) {
+	src := s.getSource(sourceName)
+	if src == nil {
+		return
+	}
+
+	s.syncs.Add(1)
+	go func() {
+		defer s.syncs.Done()
+		ctx, cancel := context.WithTimeout(s.ctx, syncTimeout)
+		defer cancel()
+
+		if err := fn(ctx, src); err != nil {
+			logger.Errorf("同步%s到 %s 失败: %v", what, sourceName, err)
+		}
+	}()
+}
+
+// waitSyncs 等待后台同步操作完成，最多等待 syncTimeout
+func (s *Scheduler) waitSyncs() {
+	done := make(chan struct{})
+	go func() {
+		s.syncs.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(syncTimeout):
+		logger.Warn("等待同步到站点超时")
+	}
+}
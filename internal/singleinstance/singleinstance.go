@@ -1,52 +1,60 @@
 package singleinstance
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"syscall"
+	"sync"
+	"time"
 
-	"golang.org/x/sys/windows"
 	"notifyme/internal/logger"
 )
 
+const (
+	instanceName   = "notifyme"      // 锁文件、套接字和命名管道的名称前缀
+	forwardTimeout = 3 * time.Second // 转发参数给正在运行的实例的超时时间
+)
+
+// Handler 处理其他实例转发过来的命令行参数（如 --show、--check-now、notifyme:// 链接）
+type Handler func(args []string)
+
 var (
-	mutexHandle windows.Handle
+	mu      sync.Mutex
+	handler Handler
+	pending [][]string // 设置 Handler 之前收到的参数
 )
 
-// Lock 尝试获取单实例锁
-// 如果已经有实例在运行，返回 false
-func Lock() (bool, error) {
-	name, err := syscall.UTF16PtrFromString("NotifyMe_SingleInstance_Mutex")
+// Lock 尝试获取单实例锁，并开始接收其他实例转发的参数
+// 如果已经有实例在运行，把 args 转发给它并返回 false
+func Lock(args []string) (bool, error) {
+	locked, err := lock()
 	if err != nil {
-		return false, fmt.Errorf("创建互斥体名称失败: %w", err)
+		return false, err
 	}
-
-	handle, err := windows.CreateMutex(nil, false, name)
-	if err != nil {
-		if err == windows.ERROR_ALREADY_EXISTS {
-			logger.Info("检测到已有实例在运行，退出当前实例")
-			return false, nil
-		}
-		return false, fmt.Errorf("创建互斥体失败: %w", err)
+	if locked {
+		logger.Info("单实例锁已获取")
+		return true, nil
 	}
 
-	mutexHandle = handle
-	logger.Info("单实例锁已获取")
-	return true, nil
+	logger.Info("检测到已有实例在运行，把参数转发给正在运行的实例")
+	if err := forward(args); err != nil {
+		return false, fmt.Errorf("转发参数给正在运行的实例失败: %w", err)
+	}
+	return false, nil
 }
 
-// Unlock 释放单实例锁
+// Unlock 释放单实例锁，停止接收转发的参数
 func Unlock() {
-	if mutexHandle != 0 {
-		windows.CloseHandle(mutexHandle)
-		mutexHandle = 0
+	if unlock() {
 		logger.Info("单实例锁已释放")
 	}
 }
 
-// CheckAndExit 检查是否有其他实例在运行，如果有则退出
-func CheckAndExit() {
-	locked, err := Lock()
+// CheckAndExit 检查是否有其他实例在运行，如果有则把 args 转发给它并退出
+func CheckAndExit(args []string) {
+	locked, err := Lock(args)
 	if err != nil {
 		logger.Errorf("检查单实例失败: %v", err)
 		os.Exit(1)
@@ -56,3 +64,68 @@ func CheckAndExit() {
 	}
 }
 
+// SetHandler 设置处理转发参数的回调，设置前收到的参数会立即交给回调处理
+func SetHandler(h Handler) {
+	mu.Lock()
+	handler = h
+	queued := pending
+	pending = nil
+	mu.Unlock()
+
+	for _, args := range queued {
+		h(args)
+	}
+}
+
+// dispatch 把收到的参数交给回调，未设置回调时先缓存
+func dispatch(args []string) {
+	mu.Lock()
+	h := handler
+	if h == nil {
+		pending = append(pending, args)
+	}
+	mu.Unlock()
+
+	if h != nil {
+		h(args)
+	}
+}
+
+// serveConn 读取一个连接转发过来的参数（一行 JSON 字符串数组）
+func serveConn(conn io.ReadWriteCloser) {
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		logger.Warnf("读取转发的参数失败: %v", err)
+		return
+	}
+	var args []string
+	if err := json.Unmarshal(line, &args); err != nil {
+		logger.Warnf("解析转发的参数失败: %v", err)
+		return
+	}
+
+	logger.Infof("收到其他实例转发的参数: %v", args)
+	// 回复后再处理，避免转发方等待界面操作
+	conn.Write([]byte("ok\n"))
+	dispatch(args)
+}
+
+// sendArgs 通过连接发送参数，并等待正在运行的实例确认
+func sendArgs(conn io.ReadWriter, args []string) error {
+	if args == nil {
+		args = []string{}
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return fmt.Errorf("序列化参数失败: %w", err)
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("发送参数失败: %w", err)
+	}
+	if _, err := bufio.NewReader(conn).ReadString('\n'); err != nil {
+		return fmt.Errorf("等待确认失败: %w", err)
+	}
+	return nil
+}
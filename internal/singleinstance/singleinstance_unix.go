@@ -0,0 +1,105 @@
+//go:build unix
+
+package singleinstance
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"notifyme/internal/logger"
+)
+
+var (
+	lockFile *os.File     // 持有 flock 的锁文件
+	listener net.Listener // 接收转发参数的 Unix 套接字
+)
+
+// runtimeDir 锁文件和套接字所在目录：优先使用 $XDG_RUNTIME_DIR（只有当前用户可访问），否则使用临时目录
+func runtimeDir() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// instancePath 当前用户的锁文件或套接字路径，如 /run/user/1000/notifyme-1000.lock
+func instancePath(ext string) string {
+	return filepath.Join(runtimeDir(), fmt.Sprintf("%s-%d.%s", instanceName, os.Getuid(), ext))
+}
+
+// lock 用 flock 锁住锁文件，成功后监听 Unix 套接字
+// 进程退出时 flock 会自动释放，残留的套接字文件在下次获取锁后删除
+func lock() (bool, error) {
+	file, err := os.OpenFile(instancePath("lock"), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return false, fmt.Errorf("打开锁文件失败: %w", err)
+	}
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return false, nil
+		}
+		return false, fmt.Errorf("锁定锁文件失败: %w", err)
+	}
+
+	socketPath := instancePath("sock")
+	os.Remove(socketPath)
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+		return false, fmt.Errorf("监听套接字失败: %w", err)
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		logger.Warnf("设置套接字权限失败: %v", err)
+	}
+
+	lockFile = file
+	listener = l
+	go accept(l)
+	return true, nil
+}
+
+// accept 接收其他实例的连接，直到套接字被关闭
+func accept(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logger.Warnf("接收其他实例的连接失败: %v", err)
+			}
+			return
+		}
+		conn.SetDeadline(time.Now().Add(forwardTimeout))
+		go serveConn(conn)
+	}
+}
+
+// unlock 关闭套接字并释放锁文件，没有持有锁时返回 false
+func unlock() bool {
+	if lockFile == nil {
+		return false
+	}
+	listener.Close()
+	listener = nil
+	syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+	lockFile.Close()
+	lockFile = nil
+	return true
+}
+
+// forward 连接正在运行的实例的套接字并发送参数
+func forward(args []string) error {
+	conn, err := net.DialTimeout("unix", instancePath("sock"), forwardTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(forwardTimeout))
+	return sendArgs(conn, args)
+}
@@ -0,0 +1,230 @@
+//go:build windows
+
+package singleinstance
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"notifyme/internal/logger"
+)
+
+const pipeBufferSize = 4096 // 命名管道缓冲区大小
+
+var (
+	mutexHandle windows.Handle
+	closed      atomic.Bool // Unlock 后停止接收连接
+)
+
+// pipeName 当前用户的命名管道名称（命名管道在所有会话间共享，按用户名区分）
+func pipeName() string {
+	return fmt.Sprintf(`\\.\pipe\%s-%s`, instanceName, os.Getenv("USERNAME"))
+}
+
+// lock 创建命名互斥体，成功后开始在命名管道上接收连接
+func lock() (bool, error) {
+	name, err := windows.UTF16PtrFromString("NotifyMe_SingleInstance_Mutex")
+	if err != nil {
+		return false, fmt.Errorf("创建互斥体名称失败: %w", err)
+	}
+
+	handle, err := windows.CreateMutex(nil, false, name)
+	if err != nil {
+		if err == windows.ERROR_ALREADY_EXISTS {
+			if handle != 0 {
+				windows.CloseHandle(handle)
+			}
+			return false, nil
+		}
+		return false, fmt.Errorf("创建互斥体失败: %w", err)
+	}
+
+	mutexHandle = handle
+	closed.Store(false)
+	go servePipe()
+	return true, nil
+}
+
+// servePipe 循环创建命名管道实例并等待其他实例连接
+func servePipe() {
+	name := pipeName()
+	for !closed.Load() {
+		pipe, err := createPipe(name)
+		if err != nil {
+			logger.Errorf("创建命名管道失败: %v", err)
+			return
+		}
+
+		if err := connectPipe(pipe); err != nil {
+			windows.CloseHandle(pipe)
+			logger.Warnf("等待其他实例连接失败: %v", err)
+			continue
+		}
+		if closed.Load() {
+			windows.CloseHandle(pipe)
+			return
+		}
+		conn := &pipeConn{handle: pipe, server: true}
+		conn.setTimeout(forwardTimeout)
+		go serveConn(conn)
+	}
+}
+
+// createPipe 创建一个命名管道实例，使用重叠 I/O 以便读写可以超时
+func createPipe(name string) (windows.Handle, error) {
+	namePtr, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, fmt.Errorf("创建命名管道名称失败: %w", err)
+	}
+	return windows.CreateNamedPipe(namePtr,
+		windows.PIPE_ACCESS_DUPLEX|windows.FILE_FLAG_OVERLAPPED,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_READMODE_BYTE|windows.PIPE_WAIT|windows.PIPE_REJECT_REMOTE_CLIENTS,
+		windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, nil)
+}
+
+// connectPipe 等待客户端连接到命名管道实例
+// 客户端在 CreateNamedPipe 和 ConnectNamedPipe 之间连接时返回 ERROR_PIPE_CONNECTED，同样视为已连接
+func connectPipe(pipe windows.Handle) error {
+	_, err := waitOverlapped(pipe, time.Time{}, func(ov *windows.Overlapped) error {
+		return windows.ConnectNamedPipe(pipe, ov)
+	})
+	if err != nil && err != windows.ERROR_PIPE_CONNECTED {
+		return err
+	}
+	return nil
+}
+
+// unlock 释放互斥体并停止接收连接，没有持有锁时返回 false
+func unlock() bool {
+	if mutexHandle == 0 {
+		return false
+	}
+	closed.Store(true)
+	// 连接一次自己的命名管道，唤醒阻塞在 ConnectNamedPipe 上的 servePipe
+	if conn, err := dialPipe(pipeName()); err == nil {
+		conn.Close()
+	}
+	windows.CloseHandle(mutexHandle)
+	mutexHandle = 0
+	return true
+}
+
+// forward 连接正在运行的实例的命名管道并发送参数
+func forward(args []string) error {
+	conn, err := dialPipe(pipeName())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.setTimeout(forwardTimeout)
+	return sendArgs(conn, args)
+}
+
+// dialPipe 连接命名管道，所有管道实例都忙或正在重新创建时短暂重试
+func dialPipe(name string) (*pipeConn, error) {
+	namePtr, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, fmt.Errorf("创建命名管道名称失败: %w", err)
+	}
+
+	deadline := time.Now().Add(forwardTimeout)
+	for {
+		handle, err := windows.CreateFile(namePtr, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING, windows.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			return &pipeConn{handle: handle}, nil
+		}
+		if !errors.Is(err, windows.ERROR_PIPE_BUSY) && !errors.Is(err, windows.ERROR_FILE_NOT_FOUND) {
+			return nil, fmt.Errorf("连接命名管道失败: %w", err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("连接命名管道超时: %w", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// errPipeTimeout 命名管道读写超过截止时间
+var errPipeTimeout = errors.New("命名管道读写超时")
+
+// pipeConn 命名管道连接
+// 句柄以重叠 I/O 方式打开，每次读写都等待到截止时间为止，超时后取消该次读写并返回 errPipeTimeout
+type pipeConn struct {
+	handle   windows.Handle
+	server   bool      // 服务端关闭前需要断开连接
+	deadline time.Time // 读写截止时间，为零表示不限制
+}
+
+// setTimeout 设置连接的超时时间，与 Unix 套接字的 SetDeadline 作用相同
+func (c *pipeConn) setTimeout(timeout time.Duration) {
+	c.deadline = time.Now().Add(timeout)
+}
+
+func (c *pipeConn) Read(p []byte) (int, error) {
+	n, err := waitOverlapped(c.handle, c.deadline, func(ov *windows.Overlapped) error {
+		return windows.ReadFile(c.handle, p, nil, ov)
+	})
+	if err == windows.ERROR_BROKEN_PIPE {
+		// 对方已关闭连接
+		return int(n), io.EOF
+	}
+	return int(n), err
+}
+
+func (c *pipeConn) Write(p []byte) (int, error) {
+	n, err := waitOverlapped(c.handle, c.deadline, func(ov *windows.Overlapped) error {
+		return windows.WriteFile(c.handle, p, nil, ov)
+	})
+	return int(n), err
+}
+
+func (c *pipeConn) Close() error {
+	if c.server {
+		windows.FlushFileBuffers(c.handle)
+		windows.DisconnectNamedPipe(c.handle)
+	}
+	return windows.CloseHandle(c.handle)
+}
+
+// waitOverlapped 发起一次重叠 I/O 并等待完成，返回传输的字节数
+// deadline 不为零时最多等待到截止时间，超时后取消该次操作（CancelIoEx）并等待取消完成，返回 errPipeTimeout
+func waitOverlapped(handle windows.Handle, deadline time.Time, start func(ov *windows.Overlapped) error) (uint32, error) {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, fmt.Errorf("创建事件失败: %w", err)
+	}
+	defer windows.CloseHandle(event)
+
+	ov := &windows.Overlapped{HEvent: event}
+	var n uint32
+	if err := start(ov); err != nil {
+		if err != windows.ERROR_IO_PENDING {
+			return 0, err
+		}
+
+		timeout := uint32(windows.INFINITE)
+		if !deadline.IsZero() {
+			timeout = uint32(max(time.Until(deadline), 0).Milliseconds())
+		}
+		result, err := windows.WaitForSingleObject(event, timeout)
+		if err != nil {
+			windows.CancelIoEx(handle, ov)
+			windows.GetOverlappedResult(handle, ov, &n, true)
+			return n, fmt.Errorf("等待命名管道读写失败: %w", err)
+		}
+		if result == uint32(windows.WAIT_TIMEOUT) {
+			// 操作取消（或恰好完成）之前 ov 仍被系统使用，需要等待
+			windows.CancelIoEx(handle, ov)
+			windows.GetOverlappedResult(handle, ov, &n, true)
+			return n, errPipeTimeout
+		}
+	}
+	if err := windows.GetOverlappedResult(handle, ov, &n, false); err != nil {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,76 @@
+//go:build windows
+
+package singleinstance
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// testPipe 创建测试用的命名管道并连接，返回服务端和客户端连接
+func testPipe(t *testing.T) (*pipeConn, *pipeConn) {
+	t.Helper()
+	name := fmt.Sprintf(`\\.\pipe\%s-test-%d-%d`, instanceName, os.Getpid(), time.Now().UnixNano())
+	pipe, err := createPipe(name)
+	if err != nil {
+		t.Fatalf("createPipe 失败: %v", err)
+	}
+
+	connected := make(chan error, 1)
+	go func() { connected <- connectPipe(pipe) }()
+
+	client, err := dialPipe(name)
+	if err != nil {
+		t.Fatalf("dialPipe 失败: %v", err)
+	}
+	if err := <-connected; err != nil {
+		t.Fatalf("connectPipe 失败: %v", err)
+	}
+	server := &pipeConn{handle: pipe, server: true}
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return server, client
+}
+
+func TestPipeForward(t *testing.T) {
+	server, client := testPipe(t)
+	server.setTimeout(forwardTimeout)
+	client.setTimeout(forwardTimeout)
+
+	received := make(chan []string, 1)
+	SetHandler(func(args []string) { received <- args })
+	defer SetHandler(nil)
+	go serveConn(server)
+
+	if err := sendArgs(client, []string{"--show"}); err != nil {
+		t.Fatalf("sendArgs 失败: %v", err)
+	}
+	select {
+	case args := <-received:
+		if len(args) != 1 || args[0] != "--show" {
+			t.Errorf("收到的参数 = %v, want [--show]", args)
+		}
+	case <-time.After(forwardTimeout):
+		t.Fatal("没有收到转发的参数")
+	}
+}
+
+func TestPipeReadTimeout(t *testing.T) {
+	server, _ := testPipe(t)
+
+	// 客户端连接后不发送任何数据，服务端的读取应在截止时间后返回
+	server.setTimeout(200 * time.Millisecond)
+	start := time.Now()
+	_, err := server.Read(make([]byte, 16))
+	if !errors.Is(err, errPipeTimeout) {
+		t.Fatalf("Read = %v, want errPipeTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("读取超时后 %v 才返回", elapsed)
+	}
+}
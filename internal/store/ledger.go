@@ -0,0 +1,201 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"notifyme/pkg/types"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Undelivered 从一批通知中筛选出尚未投递过的通知
+// 同一条通知时间更新（如 GitHub 线程有新动态）后视为新的通知，需要重新投递
+func (s *Store) Undelivered(notifications []*types.Notification) ([]*types.Notification, error) {
+	result := make([]*types.Notification, 0, len(notifications))
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketDelivered)
+		for _, notification := range notifications {
+			if bucket.Get(deliveryKey(notification)) == nil {
+				result = append(result, notification)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// MarkDelivered 记录通知已投递
+func (s *Store) MarkDelivered(notifications []*types.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return markDeliveredTx(tx, notifications)
+	})
+}
+
+// markDeliveredTx 在事务中记录通知已投递
+func markDeliveredTx(tx *bolt.Tx, notifications []*types.Notification) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(time.Now().UnixMilli()))
+
+	bucket := tx.Bucket(bucketDelivered)
+	for _, notification := range notifications {
+		if err := bucket.Put(deliveryKey(notification), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneDelivered 删除早于 before 的投递记录，返回删除的条数
+func (s *Store) PruneDelivered(before time.Time) (int, error) {
+	cutoff := uint64(before.UnixMilli())
+	count := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketDelivered)
+
+		// 先收集再删除，避免遍历过程中删除导致游标跳过键
+		var expired [][]byte
+		bucket.ForEach(func(k, v []byte) error {
+			if len(v) != 8 || binary.BigEndian.Uint64(v) < cutoff {
+				expired = append(expired, append([]byte{}, k...))
+			}
+			return nil
+		})
+
+		for _, k := range expired {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		count = len(expired)
+		return nil
+	})
+	return count, err
+}
+
+// Defer 暂缓提醒一批通知（勿扰期间），同时记为已投递，之后由 Deferred 读取汇总提醒
+func (s *Store) Defer(notifications []*types.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	// 投递记录和暂缓记录在同一事务中写入，避免中途失败时通知被记为已投递却没有暂缓
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := markDeliveredTx(tx, notifications); err != nil {
+			return err
+		}
+		bucket := tx.Bucket(bucketDeferred)
+		for _, notification := range notifications {
+			data, err := json.Marshal(notification)
+			if err != nil {
+				return fmt.Errorf("序列化通知失败: %w", err)
+			}
+			if err := bucket.Put([]byte(notification.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeferredCount 获取暂缓提醒的通知条数
+func (s *Store) DeferredCount() (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket(bucketDeferred).Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+// Deferred 读取所有暂缓提醒的通知，汇总提醒成功后由 ClearDeferred 删除
+func (s *Store) Deferred() ([]*types.Notification, error) {
+	var notifications []*types.Notification
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketDeferred).ForEach(func(k, v []byte) error {
+			var notification types.Notification
+			if err := json.Unmarshal(v, &notification); err != nil {
+				return fmt.Errorf("解析通知 %s 失败: %w", string(k), err)
+			}
+			notifications = append(notifications, &notification)
+			return nil
+		})
+	})
+	return notifications, err
+}
+
+// ClearDeferred 删除已汇总提醒的暂缓记录，之后暂缓的通知不受影响
+func (s *Store) ClearDeferred(notifications []*types.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketDeferred)
+		for _, notification := range notifications {
+			if err := bucket.Delete([]byte(notification.ID)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Cursor 获取数据源的增量拉取位置，不存在时返回空字符串
+func (s *Store) Cursor(source string) (string, error) {
+	var cursor string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		cursor = string(tx.Bucket(bucketCursors).Get([]byte(source)))
+		return nil
+	})
+	return cursor, err
+}
+
+// SetCursor 保存数据源的增量拉取位置
+func (s *Store) SetCursor(source, cursor string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketCursors).Put([]byte(source), []byte(cursor))
+	})
+}
+
+// MuteThread 静音会话
+func (s *Store) MuteThread(key string) error {
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(time.Now().UnixMilli()))
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMuted).Put([]byte(key), value)
+	})
+}
+
+// UnmuteThread 取消静音会话
+func (s *Store) UnmuteThread(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketMuted).Delete([]byte(key))
+	})
+}
+
+// MutedThreads 从一批通知中找出所在会话已静音的通知，返回通知 ID 集合
+func (s *Store) MutedThreads(notifications []*types.Notification) (map[string]bool, error) {
+	muted := make(map[string]bool)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketMuted)
+		for _, notification := range notifications {
+			if bucket.Get([]byte(notification.Thread())) != nil {
+				muted[notification.ID] = true
+			}
+		}
+		return nil
+	})
+	return muted, err
+}
+
+// deliveryKey 计算通知在投递记录中的键：通知 ID + 0x00 + 通知时间
+func deliveryKey(notification *types.Notification) []byte {
+	key := make([]byte, 0, len(notification.ID)+9)
+	key = append(key, notification.ID...)
+	key = append(key, 0)
+	return binary.BigEndian.AppendUint64(key, uint64(notification.Time))
+}
@@ -0,0 +1,240 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"notifyme/internal/logger"
+	"notifyme/pkg/types"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// keySchemaVersion 元信息中保存数据库结构版本的键
+var keySchemaVersion = []byte("schema_version")
+
+// migration 数据库结构升级步骤，把数据从 version-1 升级到 version
+type migration struct {
+	version int
+	name    string
+	apply   func(tx *bolt.Tx) error
+}
+
+// migrations 按版本排列的升级步骤，新增步骤时追加到末尾
+var migrations = []migration{
+	{version: 1, name: "建立会话索引", apply: rebuildThreadIndex},
+	{version: 2, name: "补全通知的结构化字段", apply: upgradeNotifications},
+	{version: 3, name: "GitHub 通知按更新时间重新编号", apply: renameGitHubNotifications},
+	{version: 4, name: "建立已读和归档状态索引", apply: rebuildStateIndex},
+}
+
+// schemaVersion 当前数据库结构版本
+func schemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// migrate 在事务中把数据库升级到当前结构版本，新建的数据库直接记为当前版本
+func migrate(tx *bolt.Tx, created bool) error {
+	meta := tx.Bucket(bucketMeta)
+	if created {
+		return putVersion(meta, schemaVersion())
+	}
+
+	current := 0
+	if value := meta.Get(keySchemaVersion); value != nil {
+		v, err := strconv.Atoi(string(value))
+		if err != nil {
+			return fmt.Errorf("无效的数据库版本 %s: %w", string(value), err)
+		}
+		current = v
+	}
+	if current > schemaVersion() {
+		return fmt.Errorf("数据库版本 %d 高于程序支持的版本 %d，请升级程序", current, schemaVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		logger.Infof("升级通知数据库到版本 %d: %s", m.version, m.name)
+		if err := m.apply(tx); err != nil {
+			return fmt.Errorf("升级通知数据库到版本 %d 失败: %w", m.version, err)
+		}
+		if err := putVersion(meta, m.version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putVersion 保存数据库结构版本
+func putVersion(meta *bolt.Bucket, version int) error {
+	return meta.Put(keySchemaVersion, []byte(strconv.Itoa(version)))
+}
+
+// rebuildThreadIndex 为所有通知建立会话索引
+func rebuildThreadIndex(tx *bolt.Tx) error {
+	index := tx.Bucket(bucketIdxThread)
+	return tx.Bucket(bucketNotifications).ForEach(func(_, data []byte) error {
+		var notification types.Notification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			return nil
+		}
+		return index.Put(indexKeys(&notification)[string(bucketIdxThread)], []byte{})
+	})
+}
+
+// rebuildStateIndex 为所有通知建立已读和归档状态索引
+func rebuildStateIndex(tx *bolt.Tx) error {
+	index := tx.Bucket(bucketIdxState)
+	return tx.Bucket(bucketNotifications).ForEach(func(_, data []byte) error {
+		var notification types.Notification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			return nil
+		}
+		return index.Put(indexKeys(&notification)[string(bucketIdxState)], []byte{})
+	})
+}
+
+// upgradeNotifications 为旧版本保存的通知补全结构化字段，并按新的会话标识重建索引
+func upgradeNotifications(tx *bolt.Tx) error {
+	// 先收集再修改，避免遍历过程中修改同一个桶
+	var notifications []*types.Notification
+	err := tx.Bucket(bucketNotifications).ForEach(func(id, data []byte) error {
+		var notification types.Notification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			logger.Warnf("解析通知 %s 失败，跳过升级: %v", string(id), err)
+			return nil
+		}
+		notifications = append(notifications, &notification)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, notification := range notifications {
+		if err := deleteIndexes(tx, notification); err != nil {
+			return err
+		}
+		upgradeNotification(notification)
+		if err := putTx(tx, notification); err != nil {
+			return err
+		}
+	}
+	logger.Infof("已升级 %d 条通知", len(notifications))
+	return nil
+}
+
+// upgradeNotification 根据旧版本的通知 ID 和链接补全会话标识、通知类型等字段，已有的字段保持不变
+// 旧版本的 ID 格式：github_<线程 ID>（由版本 3 改为按更新编号）、ld246_article_<帖子 ID>[_<更新时间>]、ld246_<分类>_<消息 ID>、ld246_chat_<未读数>
+func upgradeNotification(notification *types.Notification) {
+	// 旧版本没有记录站点上的未读状态，以本地已读状态为准
+	if !notification.Read {
+		notification.Unread = true
+	}
+
+	switch notification.Source {
+	case "github":
+		if notification.ThreadKey == "" {
+			notification.ThreadKey = notification.ID
+		}
+	case "ld246":
+		rest, ok := strings.CutPrefix(notification.ID, "ld246_")
+		if !ok {
+			return
+		}
+		kind, id, _ := strings.Cut(rest, "_")
+		if notification.Kind == "" {
+			notification.Kind = kind
+		}
+
+		if notification.ThreadKey != "" {
+			return
+		}
+		switch kind {
+		case "article":
+			articleID, _, _ := strings.Cut(id, "_")
+			notification.ThreadKey = "ld246_article_" + articleID
+			if notification.Extra == nil {
+				notification.Extra = map[string]string{"article_id": articleID}
+			}
+		case "chat":
+			notification.ThreadKey = "ld246_chat"
+		default:
+			// 消息通知的链接指向关联数据 /article/<dataId>
+			if _, dataID, ok := strings.Cut(notification.Link, "/article/"); ok && dataID != "" {
+				notification.ThreadKey = "ld246_article_" + dataID
+			}
+		}
+	}
+}
+
+// renameGitHubNotifications 把旧版本按线程保存的 GitHub 通知（github_<线程 ID>）改为按更新保存（github_<线程 ID>_<更新时间>），
+// 同时改写投递记录和暂缓记录的键，避免重新拉取到的同一条更新被当作新通知再次提醒
+func renameGitHubNotifications(tx *bolt.Tx) error {
+	var notifications []*types.Notification
+	err := tx.Bucket(bucketNotifications).ForEach(func(id, data []byte) error {
+		rest, ok := strings.CutPrefix(string(id), "github_")
+		if !ok || strings.Contains(rest, "_") {
+			return nil
+		}
+		var notification types.Notification
+		if err := json.Unmarshal(data, &notification); err != nil {
+			logger.Warnf("解析通知 %s 失败，跳过升级: %v", string(id), err)
+			return nil
+		}
+		notifications = append(notifications, &notification)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	delivered := tx.Bucket(bucketDelivered)
+	deferred := tx.Bucket(bucketDeferred)
+	for _, notification := range notifications {
+		oldID := notification.ID
+		oldKey := deliveryKey(notification)
+		if err := deleteIndexes(tx, notification); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketNotifications).Delete([]byte(oldID)); err != nil {
+			return err
+		}
+
+		if notification.ThreadKey == "" {
+			notification.ThreadKey = oldID
+		}
+		notification.ID = fmt.Sprintf("%s_%d", oldID, notification.Time)
+		if err := putTx(tx, notification); err != nil {
+			return err
+		}
+
+		if value := delivered.Get(oldKey); value != nil {
+			value = append([]byte{}, value...)
+			if err := delivered.Delete(oldKey); err != nil {
+				return err
+			}
+			if err := delivered.Put(deliveryKey(notification), value); err != nil {
+				return err
+			}
+		}
+		if deferred.Get([]byte(oldID)) != nil {
+			data, err := json.Marshal(notification)
+			if err != nil {
+				return fmt.Errorf("序列化通知失败: %w", err)
+			}
+			if err := deferred.Delete([]byte(oldID)); err != nil {
+				return err
+			}
+			if err := deferred.Put([]byte(notification.ID), data); err != nil {
+				return err
+			}
+		}
+	}
+	logger.Infof("已重新编号 %d 条 GitHub 通知", len(notifications))
+	return nil
+}
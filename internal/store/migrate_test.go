@@ -0,0 +1,214 @@
+package store
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"notifyme/pkg/types"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestUpgradeNotification(t *testing.T) {
+	tests := []struct {
+		name string
+		in   types.Notification
+		want types.Notification
+	}{
+		{
+			name: "GitHub 线程",
+			in:   types.Notification{ID: "github_1", Source: "github"},
+			want: types.Notification{ID: "github_1", Source: "github", ThreadKey: "github_1", Unread: true},
+		},
+		{
+			name: "ld246 帖子更新",
+			in:   types.Notification{ID: "ld246_article_42_1700000000000", Source: "ld246", Read: true},
+			want: types.Notification{ID: "ld246_article_42_1700000000000", Source: "ld246", Read: true,
+				Kind: "article", ThreadKey: "ld246_article_42", Extra: map[string]string{"article_id": "42"}},
+		},
+		{
+			name: "ld246 消息通知按链接归入帖子",
+			in:   types.Notification{ID: "ld246_at_7", Source: "ld246", Link: "https://ld246.com/article/42"},
+			want: types.Notification{ID: "ld246_at_7", Source: "ld246", Link: "https://ld246.com/article/42",
+				Kind: "at", ThreadKey: "ld246_article_42", Unread: true},
+		},
+		{
+			name: "ld246 聊天",
+			in:   types.Notification{ID: "ld246_chat_3", Source: "ld246"},
+			want: types.Notification{ID: "ld246_chat_3", Source: "ld246", Kind: "chat", ThreadKey: "ld246_chat", Unread: true},
+		},
+		{
+			name: "已有的字段保持不变",
+			in:   types.Notification{ID: "ld246_at_7", Source: "ld246", Kind: "reply", ThreadKey: "custom", Read: true},
+			want: types.Notification{ID: "ld246_at_7", Source: "ld246", Kind: "reply", ThreadKey: "custom", Read: true},
+		},
+		{
+			name: "无法识别的 ID",
+			in:   types.Notification{ID: "other", Source: "ld246", Read: true},
+			want: types.Notification{ID: "other", Source: "ld246", Read: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			upgradeNotification(&got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("upgradeNotification = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// createLegacy 创建旧版本（没有会话索引和版本号）的数据库，只包含通知、时间索引和投递记录
+func createLegacy(t *testing.T, path string, notifications []*types.Notification) {
+	t.Helper()
+	db, err := bolt.Open(path, 0644, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket(bucketNotifications)
+		if err != nil {
+			return err
+		}
+		index, err := tx.CreateBucket(bucketIdxTime)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucket(bucketDelivered); err != nil {
+			return err
+		}
+		for _, notification := range notifications {
+			data, _ := json.Marshal(notification)
+			if err := bucket.Put([]byte(notification.ID), data); err != nil {
+				return err
+			}
+			if err := index.Put(indexKeys(notification)[string(bucketIdxTime)], []byte{}); err != nil {
+				return err
+			}
+		}
+		return markDeliveredTx(tx, notifications)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// storedVersion 读取数据库中保存的结构版本
+func storedVersion(t *testing.T, s *Store) int {
+	t.Helper()
+	var version int
+	s.db.View(func(tx *bolt.Tx) error {
+		version, _ = strconv.Atoi(string(tx.Bucket(bucketMeta).Get(keySchemaVersion)))
+		return nil
+	})
+	return version
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.db")
+	createLegacy(t, path, []*types.Notification{
+		{ID: "ld246_at_1", Source: "ld246", Time: 1700000100000, Link: "https://ld246.com/article/42"},
+		{ID: "ld246_article_42_1700000200000", Source: "ld246", Time: 1700000200000},
+		{ID: "github_9", Source: "github", Time: 1700000300},
+	})
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open 失败: %v", err)
+	}
+	defer s.Close()
+
+	if version := storedVersion(t, s); version != schemaVersion() {
+		t.Errorf("schema_version = %d, want %d", version, schemaVersion())
+	}
+
+	// 升级后按新的会话标识建立索引
+	thread, err := s.Thread("ld246_article_42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"ld246_article_42_1700000200000", "ld246_at_1"}; !reflect.DeepEqual(ids(thread), want) {
+		t.Errorf("Thread = %v, want %v", ids(thread), want)
+	}
+	if got, _ := s.Get("github_9_1700000300"); got == nil || got.ThreadKey != "github_9" || !got.Unread {
+		t.Errorf("GitHub 通知升级结果 = %+v", got)
+	}
+	threads, _ := s.Threads(types.NotificationQuery{})
+	if len(threads) != 2 {
+		t.Errorf("会话数 = %d, want 2", len(threads))
+	}
+
+	// 升级后建立了状态索引
+	no := false
+	if count, err := s.Count(types.NotificationQuery{Read: &no, Archived: &no}); err != nil || count != 3 {
+		t.Errorf("未读的收件箱 = %d, %v, want 3", count, err)
+	}
+}
+
+func TestMigrateGitHubRefetch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.db")
+	createLegacy(t, path, []*types.Notification{
+		{ID: "github_9", Source: "github", Time: 1700000300, Read: true},
+	})
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open 失败: %v", err)
+	}
+	defer s.Close()
+
+	if got, _ := s.Get("github_9"); got != nil {
+		t.Errorf("旧 ID 的通知应已改名: %+v", got)
+	}
+
+	// 升级后重新拉取到同一条更新：不新增通知、保留已读状态，也不会再次提醒
+	refetched := &types.Notification{ID: "github_9_1700000300", ThreadKey: "github_9", Source: "github", Time: 1700000300}
+	added, err := s.Put([]*types.Notification{refetched})
+	if err != nil || added != 0 {
+		t.Errorf("Put = %d, %v, want 0", added, err)
+	}
+	if got, _ := s.Get(refetched.ID); got == nil || !got.Read {
+		t.Errorf("重新拉取后应保留已读状态: %+v", got)
+	}
+	if pending, _ := s.Undelivered([]*types.Notification{refetched}); len(pending) != 0 {
+		t.Errorf("Undelivered = %v, want 空", ids(pending))
+	}
+	list, _ := s.List(types.NotificationQuery{})
+	if want := []string{"github_9_1700000300"}; !reflect.DeepEqual(ids(list), want) {
+		t.Errorf("List = %v, want %v", ids(list), want)
+	}
+
+	// 之后的新动态仍然按新通知提醒
+	update := &types.Notification{ID: "github_9_1700000400", ThreadKey: "github_9", Source: "github", Time: 1700000400}
+	if pending, _ := s.Undelivered([]*types.Notification{update}); len(pending) != 1 {
+		t.Error("线程的新动态应重新提醒")
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version := storedVersion(t, s); version != schemaVersion() {
+		t.Errorf("新建数据库 schema_version = %d, want %d", version, schemaVersion())
+	}
+	s.db.Update(func(tx *bolt.Tx) error {
+		return putVersion(tx.Bucket(bucketMeta), schemaVersion()+1)
+	})
+	s.Close()
+
+	// 版本高于程序支持的版本时拒绝打开，避免旧程序破坏新数据
+	if s, err := Open(path); err == nil {
+		s.Close()
+		t.Error("打开更高版本的数据库应失败")
+	}
+}
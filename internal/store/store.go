@@ -0,0 +1,525 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"notifyme/internal/logger"
+	"notifyme/pkg/types"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketNotifications = []byte("notifications") // 通知 ID -> 通知 JSON
+	bucketIdxTime       = []byte("idx_time")      // 时间 + ID -> 空
+	bucketIdxSource     = []byte("idx_source")    // 来源 + 0x00 + 时间 + ID -> 空
+	bucketIdxRead       = []byte("idx_read")      // 已读标记 + 时间 + ID -> 空
+	bucketIdxState      = []byte("idx_state")     // 归档标记 + 已读标记 + 时间 + ID -> 空
+	bucketIdxSnooze     = []byte("idx_snooze")    // 稍后提醒时间 + ID -> 空（只包含设置了稍后提醒的通知）
+	bucketIdxThread     = []byte("idx_thread")    // 会话标识 + 0x00 + 时间 + ID -> 空
+	bucketDelivered     = []byte("delivered")     // 通知 ID + 0x00 + 通知时间 -> 投递时间（投递记录）
+	bucketCursors       = []byte("cursors")       // 数据源名称 -> 增量拉取位置
+	bucketDeferred      = []byte("deferred")      // 通知 ID -> 通知 JSON（勿扰期间暂缓提醒的通知）
+	bucketMeta          = []byte("meta")          // 数据库元信息（如 schema_version）
+	bucketMuted         = []byte("muted_threads") // 会话标识 -> 静音时间（静音的会话不再提醒）
+)
+
+// ErrNotFound 通知不存在
+var ErrNotFound = errors.New("通知不存在")
+
+// ErrLocked 通知数据库被其他进程占用（同一时间只能有一个进程打开）
+var ErrLocked = errors.New("通知数据库被其他进程占用（桌面版或守护进程是否正在运行？）")
+
+// Store 通知历史数据库
+// 基于 bbolt 嵌入式数据库，不限制条数，并按来源、时间和已读状态建立索引
+type Store struct {
+	db *bolt.DB
+}
+
+// Open 打开（或创建）通知历史数据库
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 3 * time.Second})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("%w: %v", ErrLocked, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开通知数据库失败: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		// 新建的数据库不需要迁移
+		created := tx.Bucket(bucketNotifications) == nil
+
+		for _, name := range [][]byte{bucketNotifications, bucketIdxTime, bucketIdxSource, bucketIdxRead, bucketIdxState, bucketIdxSnooze, bucketIdxThread, bucketDelivered, bucketCursors, bucketDeferred, bucketMeta, bucketMuted} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return migrate(tx, created)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化通知数据库失败: %w", err)
+	}
+
+	logger.Infof("通知数据库已打开: %s", path)
+	return &Store{db: db}, nil
+}
+
+// Close 关闭数据库
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put 写入通知，已存在的通知会被覆盖
+// 已存在通知的星标始终保留；如果时间没有变化，同时保留已读、归档和稍后提醒状态，
+// 时间更新说明有新动态，重新标记为未读并移回收件箱
+// 返回新增的通知条数
+func (s *Store) Put(notifications []*types.Notification) (int, error) {
+	added := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		for _, notification := range notifications {
+			existing, err := getTx(tx, notification.ID)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				added++
+			} else {
+				notification.Starred = existing.Starred
+				if existing.Time == notification.Time {
+					notification.Read = existing.Read
+					notification.Archived = existing.Archived
+					notification.SnoozedUntil = existing.SnoozedUntil
+				}
+				if err := deleteIndexes(tx, existing); err != nil {
+					return err
+				}
+			}
+			if err := putTx(tx, notification); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return added, err
+}
+
+// Get 按 ID 获取通知，不存在时返回 nil
+func (s *Store) Get(id string) (*types.Notification, error) {
+	var notification *types.Notification
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		notification, err = getTx(tx, id)
+		return err
+	})
+	return notification, err
+}
+
+// Update 读取并修改一条通知，通知不存在时返回错误
+func (s *Store) Update(id string, fn func(notification *types.Notification)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		notification, err := getTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if notification == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		if err := deleteIndexes(tx, notification); err != nil {
+			return err
+		}
+		fn(notification)
+		return putTx(tx, notification)
+	})
+}
+
+// List 按时间倒序查询通知
+func (s *Store) List(query types.NotificationQuery) ([]*types.Notification, error) {
+	result := make([]*types.Notification, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		skipped := 0
+		return scanQuery(tx, query, func(notification *types.Notification) bool {
+			if skipped < query.Offset {
+				skipped++
+				return true
+			}
+			result = append(result, notification)
+			return query.Limit <= 0 || len(result) < query.Limit
+		})
+	})
+	return result, err
+}
+
+// Count 统计满足查询条件的通知条数（不处理 Limit 和 Offset）
+// 查询条件只有来源、已读状态或已读和归档状态（以及时间范围）时只遍历索引，不读取通知内容
+func (s *Store) Count(query types.NotificationQuery) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var indexOnly bool
+		if query.Starred == nil && query.Snoozed == nil {
+			if query.Source != "" {
+				indexOnly = query.Read == nil && query.Archived == nil
+			} else {
+				indexOnly = query.Archived == nil || query.Read != nil
+			}
+		}
+		if !indexOnly {
+			return scanQuery(tx, query, func(*types.Notification) bool {
+				count++
+				return true
+			})
+		}
+
+		bucket, prefix := queryIndex(tx, query)
+		return scanDesc(bucket, prefix, query.Until, func(key []byte) bool {
+			if t, _ := splitIndexKey(key[len(prefix):]); query.Since > 0 && t < query.Since {
+				return false
+			}
+			count++
+			return true
+		})
+	})
+	return count, err
+}
+
+// Threads 按最近一次更新的时间倒序查询会话
+// 查询条件作用于每一条更新，会话的最近一次更新为满足条件的最新一条；Limit 和 Offset 按会话计数
+func (s *Store) Threads(query types.NotificationQuery) ([]*types.NotificationThread, error) {
+	result := make([]*types.NotificationThread, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		seen := make(map[string]bool)
+		skipped := 0
+		var scanErr error
+		err := scanQuery(tx, query, func(notification *types.Notification) bool {
+			key := notification.Thread()
+			if seen[key] {
+				return true
+			}
+			seen[key] = true
+
+			if skipped < query.Offset {
+				skipped++
+				return true
+			}
+
+			thread := &types.NotificationThread{
+				Key:    key,
+				Latest: notification,
+				Muted:  tx.Bucket(bucketMuted).Get([]byte(key)) != nil,
+			}
+			if scanErr = forEachInThread(tx, key, func(update *types.Notification) {
+				thread.Count++
+				if !update.Read {
+					thread.Unread++
+				}
+			}); scanErr != nil {
+				return false
+			}
+			result = append(result, thread)
+			return query.Limit <= 0 || len(result) < query.Limit
+		})
+		if scanErr != nil {
+			return scanErr
+		}
+		return err
+	})
+	return result, err
+}
+
+// Thread 按时间倒序获取会话中的所有更新
+func (s *Store) Thread(key string) ([]*types.Notification, error) {
+	result := make([]*types.Notification, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return forEachInThread(tx, key, func(notification *types.Notification) {
+			result = append(result, notification)
+		})
+	})
+	return result, err
+}
+
+// MarkAllRead 把满足条件的未读通知标记为已读，match 为空表示全部，返回标记的条数
+func (s *Store) MarkAllRead(match func(notification *types.Notification) bool) (int, error) {
+	count := 0
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		// 先收集再修改，避免遍历过程中修改同一个索引
+		var ids [][]byte
+		prefix := []byte{readFlag(false)}
+		scanDesc(tx.Bucket(bucketIdxRead), prefix, 0, func(key []byte) bool {
+			_, id := splitIndexKey(key[len(prefix):])
+			ids = append(ids, append([]byte{}, id...))
+			return true
+		})
+
+		for _, id := range ids {
+			notification, err := getTx(tx, string(id))
+			if err != nil {
+				return err
+			}
+			if notification == nil || (match != nil && !match(notification)) {
+				continue
+			}
+			if err := deleteIndexes(tx, notification); err != nil {
+				return err
+			}
+			notification.Read = true
+			if err := putTx(tx, notification); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// DueSnoozed 获取稍后提醒时间已到（不晚于 now，毫秒）的通知，按提醒时间排序
+func (s *Store) DueSnoozed(now int64) ([]*types.Notification, error) {
+	var result []*types.Notification
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketIdxSnooze).Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			t, id := splitIndexKey(k)
+			if t > now {
+				break
+			}
+			notification, err := getTx(tx, string(id))
+			if err != nil {
+				return err
+			}
+			if notification != nil {
+				result = append(result, notification)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ImportJSON 导入旧版 JSON 格式的通知列表文件
+// 导入成功后把原文件重命名为 .migrated，避免重复导入；文件不存在时直接返回
+func (s *Store) ImportJSON(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("读取文件失败: %w", err)
+	}
+
+	var notifications []*types.Notification
+	if err := json.Unmarshal(data, &notifications); err != nil {
+		return 0, fmt.Errorf("解析通知列表失败: %w", err)
+	}
+	for _, notification := range notifications {
+		upgradeNotification(notification)
+	}
+
+	added, err := s.Put(notifications)
+	if err != nil {
+		return 0, fmt.Errorf("写入通知数据库失败: %w", err)
+	}
+	// 旧版本已经弹出过这些通知，记为已投递，避免升级后重复提醒
+	if err := s.MarkDelivered(notifications); err != nil {
+		return 0, fmt.Errorf("写入投递记录失败: %w", err)
+	}
+
+	if err := os.Rename(path, path+".migrated"); err != nil {
+		logger.Warnf("重命名已导入的通知列表文件失败: %v", err)
+	}
+
+	logger.Infof("已从 %s 导入 %d 条通知（新增 %d 条）", path, len(notifications), added)
+	return added, nil
+}
+
+// scanQuery 按查询条件选择最合适的索引，按时间倒序遍历满足条件的通知（不处理 Limit 和 Offset）
+// fn 返回 false 时停止遍历
+func scanQuery(tx *bolt.Tx, query types.NotificationQuery, fn func(notification *types.Notification) bool) error {
+	bucket, prefix := queryIndex(tx, query)
+	notifications := tx.Bucket(bucketNotifications)
+	return scanDesc(bucket, prefix, query.Until, func(key []byte) bool {
+		t, id := splitIndexKey(key[len(prefix):])
+		if query.Since > 0 && t < query.Since {
+			return false
+		}
+
+		var notification types.Notification
+		if err := json.Unmarshal(notifications.Get(id), &notification); err != nil {
+			logger.Warnf("解析通知 %s 失败: %v", string(id), err)
+			return true
+		}
+		if !matchState(&notification, query) {
+			return true
+		}
+		return fn(&notification)
+	})
+}
+
+// queryIndex 按查询条件选择遍历的索引及键前缀：指定来源时使用来源索引，同时指定已读和归档状态时使用状态索引，
+// 只指定已读状态时使用已读索引，否则使用时间索引
+func queryIndex(tx *bolt.Tx, query types.NotificationQuery) (*bolt.Bucket, []byte) {
+	switch {
+	case query.Source != "":
+		return tx.Bucket(bucketIdxSource), append([]byte(query.Source), 0)
+	case query.Read != nil && query.Archived != nil:
+		return tx.Bucket(bucketIdxState), []byte{readFlag(*query.Archived), readFlag(*query.Read)}
+	case query.Read != nil:
+		return tx.Bucket(bucketIdxRead), []byte{readFlag(*query.Read)}
+	default:
+		return tx.Bucket(bucketIdxTime), nil
+	}
+}
+
+// forEachInThread 在事务中按时间倒序遍历会话中的所有更新
+func forEachInThread(tx *bolt.Tx, key string, fn func(notification *types.Notification)) error {
+	prefix := append([]byte(key), 0)
+	var scanErr error
+	scanDesc(tx.Bucket(bucketIdxThread), prefix, 0, func(k []byte) bool {
+		_, id := splitIndexKey(k[len(prefix):])
+		notification, err := getTx(tx, string(id))
+		if err != nil {
+			scanErr = err
+			return false
+		}
+		if notification != nil {
+			fn(notification)
+		}
+		return true
+	})
+	return scanErr
+}
+
+// getTx 在事务中按 ID 获取通知
+func getTx(tx *bolt.Tx, id string) (*types.Notification, error) {
+	data := tx.Bucket(bucketNotifications).Get([]byte(id))
+	if data == nil {
+		return nil, nil
+	}
+	var notification types.Notification
+	if err := json.Unmarshal(data, &notification); err != nil {
+		return nil, fmt.Errorf("解析通知 %s 失败: %w", id, err)
+	}
+	return &notification, nil
+}
+
+// putTx 在事务中写入通知及其索引
+func putTx(tx *bolt.Tx, notification *types.Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+	if err := tx.Bucket(bucketNotifications).Put([]byte(notification.ID), data); err != nil {
+		return err
+	}
+	for bucket, key := range indexKeys(notification) {
+		if err := tx.Bucket([]byte(bucket)).Put(key, []byte{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteIndexes 在事务中删除通知的索引
+func deleteIndexes(tx *bolt.Tx, notification *types.Notification) error {
+	for bucket, key := range indexKeys(notification) {
+		if err := tx.Bucket([]byte(bucket)).Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexKeys 计算通知在各索引中的键（索引桶名 -> 键）
+func indexKeys(notification *types.Notification) map[string][]byte {
+	timeID := make([]byte, 8, 8+len(notification.ID))
+	binary.BigEndian.PutUint64(timeID, uint64(notification.UnixMilli()))
+	timeID = append(timeID, notification.ID...)
+
+	sourceKey := append([]byte(notification.Source), 0)
+	readKey := []byte{readFlag(notification.Read)}
+	stateKey := []byte{readFlag(notification.Archived), readFlag(notification.Read)}
+	threadKey := append([]byte(notification.Thread()), 0)
+
+	keys := map[string][]byte{
+		string(bucketIdxTime):   timeID,
+		string(bucketIdxSource): append(sourceKey, timeID...),
+		string(bucketIdxRead):   append(readKey, timeID...),
+		string(bucketIdxState):  append(stateKey, timeID...),
+		string(bucketIdxThread): append(threadKey, timeID...),
+	}
+	if notification.SnoozedUntil > 0 {
+		snoozeKey := make([]byte, 8, 8+len(notification.ID))
+		binary.BigEndian.PutUint64(snoozeKey, uint64(notification.SnoozedUntil))
+		keys[string(bucketIdxSnooze)] = append(snoozeKey, notification.ID...)
+	}
+	return keys
+}
+
+// splitIndexKey 从索引键（去掉前缀后）解析出时间和通知 ID
+func splitIndexKey(key []byte) (int64, []byte) {
+	if len(key) < 8 {
+		return 0, nil
+	}
+	return int64(binary.BigEndian.Uint64(key[:8])), key[8:]
+}
+
+// matchState 检查通知状态是否满足查询条件
+func matchState(notification *types.Notification, query types.NotificationQuery) bool {
+	if query.Read != nil && notification.Read != *query.Read {
+		return false
+	}
+	if query.Archived != nil && notification.Archived != *query.Archived {
+		return false
+	}
+	if query.Starred != nil && notification.Starred != *query.Starred {
+		return false
+	}
+	if query.Snoozed != nil && (notification.SnoozedUntil > 0) != *query.Snoozed {
+		return false
+	}
+	return true
+}
+
+// readFlag 已读（或归档）状态在索引中的编码
+func readFlag(read bool) byte {
+	if read {
+		return 1
+	}
+	return 0
+}
+
+// scanDesc 按时间倒序遍历索引中指定前缀的键，until 大于 0 时只遍历早于该时间的键
+// fn 返回 false 时停止遍历
+func scanDesc(bucket *bolt.Bucket, prefix []byte, until int64, fn func(key []byte) bool) error {
+	c := bucket.Cursor()
+
+	// 定位到前缀范围（或 until 之前）的最后一个键
+	var seek []byte
+	if until > 0 {
+		seek = make([]byte, len(prefix)+8)
+		copy(seek, prefix)
+		binary.BigEndian.PutUint64(seek[len(prefix):], uint64(until))
+	} else {
+		seek = append(append([]byte{}, prefix...), bytes.Repeat([]byte{0xff}, 9)...)
+	}
+
+	k, _ := c.Seek(seek)
+	if k == nil {
+		k, _ = c.Last()
+	} else {
+		k, _ = c.Prev()
+	}
+
+	for ; k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Prev() {
+		if !fn(k) {
+			break
+		}
+	}
+	return nil
+}
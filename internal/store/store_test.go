@@ -0,0 +1,319 @@
+package store
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"notifyme/pkg/types"
+)
+
+// openTest 在临时目录中打开数据库，测试结束时关闭
+func openTest(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "notifications.db"))
+	if err != nil {
+		t.Fatalf("Open 失败: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// ids 提取通知 ID，便于比较
+func ids(notifications []*types.Notification) []string {
+	result := make([]string, 0, len(notifications))
+	for _, notification := range notifications {
+		result = append(result, notification.ID)
+	}
+	return result
+}
+
+// seed 写入一组测试通知，时间单位混合了秒（GitHub）和毫秒（ld246）
+func seed(t *testing.T, s *Store) {
+	t.Helper()
+	_, err := s.Put([]*types.Notification{
+		{ID: "github_1_100", ThreadKey: "github_1", Source: "github", Time: 1700000100},
+		{ID: "github_1_300", ThreadKey: "github_1", Source: "github", Time: 1700000300},
+		{ID: "ld246_at_1", ThreadKey: "ld246_article_1", Source: "ld246", Time: 1700000200000},
+		{ID: "ld246_at_2", ThreadKey: "ld246_article_1", Source: "ld246", Time: 1700000400000, Read: true},
+		{ID: "github_2_500", ThreadKey: "github_2", Source: "github", Time: 1700000500, Archived: true},
+	})
+	if err != nil {
+		t.Fatalf("Put 失败: %v", err)
+	}
+}
+
+func TestPut(t *testing.T) {
+	s := openTest(t)
+
+	added, err := s.Put([]*types.Notification{{ID: "a", Time: 1}, {ID: "b", Time: 2}})
+	if err != nil || added != 2 {
+		t.Fatalf("Put = %d, %v, want 2", added, err)
+	}
+	if err := s.Update("a", func(n *types.Notification) {
+		n.Read = true
+		n.Starred = true
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 时间不变：保留已读和星标
+	added, err = s.Put([]*types.Notification{{ID: "a", Time: 1, Title: "新标题"}})
+	if err != nil || added != 0 {
+		t.Fatalf("Put = %d, %v, want 0", added, err)
+	}
+	got, _ := s.Get("a")
+	if !got.Read || !got.Starred || got.Title != "新标题" {
+		t.Errorf("时间不变时应保留状态: %+v", got)
+	}
+
+	// 时间更新：重新标记为未读，星标保留
+	if _, err := s.Put([]*types.Notification{{ID: "a", Time: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.Get("a")
+	if got.Read || !got.Starred {
+		t.Errorf("时间更新后应为未读并保留星标: %+v", got)
+	}
+
+	// 旧的索引键已删除，按时间查询不会出现重复
+	list, err := s.List(types.NotificationQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(ids(list), want) {
+		t.Errorf("List = %v, want %v", ids(list), want)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s := openTest(t)
+
+	err := s.Update("missing", func(*types.Notification) {})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update 不存在的通知 = %v, want ErrNotFound", err)
+	}
+	if got, err := s.Get("missing"); got != nil || err != nil {
+		t.Errorf("Get 不存在的通知 = %v, %v, want nil, nil", got, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	s := openTest(t)
+	seed(t, s)
+
+	yes, no := true, false
+	tests := []struct {
+		name  string
+		query types.NotificationQuery
+		want  []string
+	}{
+		{"全部按时间倒序", types.NotificationQuery{}, []string{"github_2_500", "ld246_at_2", "github_1_300", "ld246_at_1", "github_1_100"}},
+		{"按来源", types.NotificationQuery{Source: "ld246"}, []string{"ld246_at_2", "ld246_at_1"}},
+		{"未读", types.NotificationQuery{Read: &no}, []string{"github_2_500", "github_1_300", "ld246_at_1", "github_1_100"}},
+		{"已读", types.NotificationQuery{Read: &yes}, []string{"ld246_at_2"}},
+		{"收件箱", types.NotificationQuery{Archived: &no}, []string{"ld246_at_2", "github_1_300", "ld246_at_1", "github_1_100"}},
+		{"来源和状态组合", types.NotificationQuery{Source: "github", Read: &no, Archived: &no}, []string{"github_1_300", "github_1_100"}},
+		{"未读的收件箱", types.NotificationQuery{Read: &no, Archived: &no}, []string{"github_1_300", "ld246_at_1", "github_1_100"}},
+		{"已归档的未读", types.NotificationQuery{Read: &no, Archived: &yes}, []string{"github_2_500"}},
+		{"时间范围（毫秒，包含起始不包含结束）", types.NotificationQuery{Since: 1700000200000, Until: 1700000400000}, []string{"github_1_300", "ld246_at_1"}},
+		{"分页", types.NotificationQuery{Limit: 2, Offset: 1}, []string{"ld246_at_2", "github_1_300"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.List(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(ids(got), tt.want) {
+				t.Errorf("List = %v, want %v", ids(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestCount(t *testing.T) {
+	s := openTest(t)
+	seed(t, s)
+
+	yes, no := true, false
+	tests := []struct {
+		name  string
+		query types.NotificationQuery
+		want  int
+	}{
+		{"全部", types.NotificationQuery{}, 5},
+		{"未读（只遍历索引）", types.NotificationQuery{Read: &no}, 4},
+		{"来源（只遍历索引）", types.NotificationQuery{Source: "ld246"}, 2},
+		{"未读的收件箱（只遍历索引）", types.NotificationQuery{Read: &no, Archived: &no}, 3},
+		{"只按归档状态", types.NotificationQuery{Archived: &no}, 4},
+		{"来源和已读状态", types.NotificationQuery{Source: "ld246", Read: &yes}, 1},
+		{"时间范围", types.NotificationQuery{Read: &no, Since: 1700000200000, Until: 1700000500000}, 2},
+		{"状态索引的时间范围", types.NotificationQuery{Read: &no, Archived: &no, Since: 1700000200000}, 2},
+		{"忽略分页", types.NotificationQuery{Limit: 1, Offset: 1}, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Count(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Count = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThreads(t *testing.T) {
+	s := openTest(t)
+	seed(t, s)
+
+	if err := s.MuteThread("github_1"); err != nil {
+		t.Fatal(err)
+	}
+	threads, err := s.Threads(types.NotificationQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type summary struct {
+		Key, Latest   string
+		Count, Unread int
+		Muted         bool
+	}
+	var got []summary
+	for _, thread := range threads {
+		got = append(got, summary{thread.Key, thread.Latest.ID, thread.Count, thread.Unread, thread.Muted})
+	}
+	want := []summary{
+		{"github_2", "github_2_500", 1, 1, false},
+		{"ld246_article_1", "ld246_at_2", 2, 1, false},
+		{"github_1", "github_1_300", 2, 2, true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Threads = %+v, want %+v", got, want)
+	}
+
+	thread, err := s.Thread("github_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"github_1_300", "github_1_100"}; !reflect.DeepEqual(ids(thread), want) {
+		t.Errorf("Thread = %v, want %v", ids(thread), want)
+	}
+}
+
+func TestMarkAllRead(t *testing.T) {
+	s := openTest(t)
+	seed(t, s)
+
+	count, err := s.MarkAllRead(func(n *types.Notification) bool { return n.Source == "github" })
+	if err != nil || count != 3 {
+		t.Fatalf("MarkAllRead = %d, %v, want 3", count, err)
+	}
+	no := false
+	unread, _ := s.List(types.NotificationQuery{Read: &no})
+	if want := []string{"ld246_at_1"}; !reflect.DeepEqual(ids(unread), want) {
+		t.Errorf("未读 = %v, want %v", ids(unread), want)
+	}
+}
+
+func TestDueSnoozed(t *testing.T) {
+	s := openTest(t)
+	s.Put([]*types.Notification{
+		{ID: "late", Time: 1, SnoozedUntil: 3000},
+		{ID: "early", Time: 2, SnoozedUntil: 1000},
+		{ID: "none", Time: 3},
+	})
+
+	due, err := s.DueSnoozed(2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"early"}; !reflect.DeepEqual(ids(due), want) {
+		t.Errorf("DueSnoozed = %v, want %v", ids(due), want)
+	}
+
+	// 清除稍后提醒后同时删除索引
+	s.Update("early", func(n *types.Notification) { n.SnoozedUntil = 0 })
+	due, _ = s.DueSnoozed(5000)
+	if want := []string{"late"}; !reflect.DeepEqual(ids(due), want) {
+		t.Errorf("DueSnoozed = %v, want %v", ids(due), want)
+	}
+}
+
+func TestLedger(t *testing.T) {
+	s := openTest(t)
+
+	a := &types.Notification{ID: "a", Time: 1}
+	b := &types.Notification{ID: "b", Time: 1}
+	if err := s.MarkDelivered([]*types.Notification{a}); err != nil {
+		t.Fatal(err)
+	}
+	pending, _ := s.Undelivered([]*types.Notification{a, b})
+	if want := []string{"b"}; !reflect.DeepEqual(ids(pending), want) {
+		t.Errorf("Undelivered = %v, want %v", ids(pending), want)
+	}
+
+	// 时间更新后视为新的通知
+	updated := &types.Notification{ID: "a", Time: 2}
+	pending, _ = s.Undelivered([]*types.Notification{updated})
+	if len(pending) != 1 {
+		t.Errorf("时间更新后应重新投递")
+	}
+
+	// 暂缓的通知同时记为已投递
+	if err := s.Defer([]*types.Notification{b}); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ = s.Undelivered([]*types.Notification{b}); len(pending) != 0 {
+		t.Errorf("暂缓的通知应记为已投递")
+	}
+	if count, _ := s.DeferredCount(); count != 1 {
+		t.Errorf("DeferredCount = %d, want 1", count)
+	}
+	deferred, err := s.Deferred()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"b"}; !reflect.DeepEqual(ids(deferred), want) {
+		t.Errorf("Deferred = %v, want %v", ids(deferred), want)
+	}
+
+	// 读取不会删除，只删除已汇总提醒的记录
+	c := &types.Notification{ID: "c", Time: 1}
+	s.Defer([]*types.Notification{c})
+	if err := s.ClearDeferred(deferred); err != nil {
+		t.Fatal(err)
+	}
+	deferred, _ = s.Deferred()
+	if want := []string{"c"}; !reflect.DeepEqual(ids(deferred), want) {
+		t.Errorf("ClearDeferred 后 Deferred = %v, want %v", ids(deferred), want)
+	}
+}
+
+func TestReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Put([]*types.Notification{{ID: "a", Time: 1}})
+	s.SetCursor("github", "etag")
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("重新打开失败: %v", err)
+	}
+	defer s.Close()
+	if got, _ := s.Get("a"); got == nil {
+		t.Error("重新打开后通知丢失")
+	}
+	if cursor, _ := s.Cursor("github"); cursor != "etag" {
+		t.Errorf("Cursor = %q, want etag", cursor)
+	}
+}
@@ -1,29 +1,32 @@
 package tray
 
 import (
-	_ "embed"
 	"fmt"
+	"notifyme/internal/assets"
 	"notifyme/internal/logger"
 	"os"
 
 	"github.com/getlantern/systray"
 )
 
-//go:embed icon.ico
-var iconData []byte
-
-// GetIconData 获取图标数据（供其他包使用）
-func GetIconData() []byte {
-	return iconData
-}
-
 var (
 	onOpenUI   func()
 	onQuit     func()
+	onPause    func(hours int)
+	onResume   func()
 	menuOpenUI *systray.MenuItem
 	menuQuit   *systray.MenuItem
 )
 
+// pauseOptions 托盘菜单中可选的暂停时长（小时）
+var pauseOptions = []int{1, 2, 4, 8}
+
+// SetPauseHandlers 设置暂停/恢复通知的回调，需要在 Init 之前调用
+func SetPauseHandlers(onPauseCallback func(hours int), onResumeCallback func()) {
+	onPause = onPauseCallback
+	onResume = onResumeCallback
+}
+
 // Init 初始化系统托盘
 func Init(onOpenUICallback, onQuitCallback func()) {
 	onOpenUI = onOpenUICallback
@@ -45,6 +48,8 @@ func onReady() {
 	// 添加菜单项
 	menuOpenUI = systray.AddMenuItem("打开界面", "打开主界面")
 	systray.AddSeparator()
+	addPauseMenu()
+	systray.AddSeparator()
 	menuQuit = systray.AddMenuItem("退出", "退出程序")
 
 	// 监听菜单点击事件
@@ -98,6 +103,32 @@ func onReady() {
 	}()
 }
 
+// addPauseMenu 添加暂停通知子菜单和恢复通知菜单项
+func addPauseMenu() {
+	menuPause := systray.AddMenuItem("暂停通知", "暂停弹出通知，期间的通知会在恢复后汇总提醒")
+	for _, hours := range pauseOptions {
+		item := menuPause.AddSubMenuItem(fmt.Sprintf("%d 小时", hours), fmt.Sprintf("暂停通知 %d 小时", hours))
+		go func(hours int) {
+			for range item.ClickedCh {
+				logger.Infof("收到暂停通知 %d 小时菜单项点击事件", hours)
+				if onPause != nil {
+					go onPause(hours)
+				}
+			}
+		}(hours)
+	}
+
+	menuResume := systray.AddMenuItem("恢复通知", "取消暂停，立即汇总提醒暂停期间的通知")
+	go func() {
+		for range menuResume.ClickedCh {
+			logger.Info("收到恢复通知菜单项点击事件")
+			if onResume != nil {
+				go onResume()
+			}
+		}
+	}()
+}
+
 // onExit 托盘退出回调
 func onExit() {
 	logger.Info("系统托盘退出")
@@ -106,10 +137,10 @@ func onExit() {
 // getIcon 获取托盘图标
 func getIcon() []byte {
 	// 如果嵌入的图标数据为空，返回 nil（systray 会使用默认图标）
-	if len(iconData) == 0 {
+	if len(assets.Icon) == 0 {
 		return nil
 	}
-	return iconData
+	return assets.Icon
 }
 
 // Quit 退出托盘
@@ -26,8 +26,8 @@ func main() {
 		panic(err)
 	}
 
-	// 检查单实例
-	singleinstance.CheckAndExit()
+	// 检查单实例，已有实例在运行时把启动参数（如 --show、--check-now、notifyme:// 链接）转发给它
+	singleinstance.CheckAndExit(os.Args[1:])
 	defer singleinstance.Unlock()
 
 	// Create an instance of the app structure
@@ -2,7 +2,8 @@ package types
 
 // GitHubAuth 表示 GitHub 认证配置
 type GitHubAuth struct {
-	Token string `json:"token"` // Personal Access Token
+	Token    string `json:"token"`     // Personal Access Token 或设备授权登录得到的 OAuth token
+	ClientID string `json:"client_id"` // OAuth App 的 Client ID（设备授权登录使用，需在 OAuth App 设置中启用 Device Flow）
 }
 
 // Ld246Config 表示 ld246 认证配置
@@ -10,6 +11,70 @@ type Ld246Config struct {
 	Token string `json:"token"` // API token
 }
 
+// SinkConfig 表示一个通知渠道配置
+type SinkConfig struct {
+	Name    string            `json:"name"`    // 渠道名称（用于日志和状态展示），为空时使用类型名
+	Type    string            `json:"type"`    // 渠道类型：desktop, webhook, file
+	Enabled bool              `json:"enabled"` // 是否启用
+	URL     string            `json:"url"`     // 推送地址（webhook）
+	Headers map[string]string `json:"headers"` // 附加请求头（webhook）
+	Path    string            `json:"path"`    // 输出文件路径（file）
+	Urgency string            `json:"urgency"` // 紧急程度：low, normal, critical（desktop，仅 Linux）
+}
+
+// SourceConfig 表示一个数据源的配置
+type SourceConfig struct {
+	Disabled     bool `json:"disabled"`      // 是否停用该数据源
+	PollInterval int  `json:"poll_interval"` // 轮询间隔（秒），为 0 时使用全局轮询间隔
+}
+
+// RuleMatch 表示规则的匹配条件，所有非空条件都满足时规则生效
+type RuleMatch struct {
+	Source     string `json:"source"`     // 来源（github、ld246）
+	Title      string `json:"title"`      // 标题（正则表达式）
+	Content    string `json:"content"`    // 内容（正则表达式）
+	Repository string `json:"repository"` // GitHub 仓库（owner/repo，支持 * 通配符，如 owner/*）
+	Reason     string `json:"reason"`     // GitHub 通知原因（如 mention、ci_activity）
+	Kind       string `json:"kind"`       // GitHub 通知类型（如 PullRequest、CheckSuite）或 ld246 消息分类（如 at、reply）
+}
+
+// Rule 表示一条通知规则
+type Rule struct {
+	Name     string    `json:"name"`     // 规则名称（用于日志）
+	Enabled  bool      `json:"enabled"`  // 是否启用
+	Match    RuleMatch `json:"match"`    // 匹配条件
+	Action   string    `json:"action"`   // 动作：drop（丢弃）、mute（不弹通知但保留历史）、route（只投递到指定渠道）、priority（设置优先级）
+	Sinks    []string  `json:"sinks"`    // 投递到的渠道名称（route）
+	Priority string    `json:"priority"` // 优先级：low, normal, high（priority）
+}
+
+// QuietWindow 表示一个勿扰时间段
+type QuietWindow struct {
+	Days  []int  `json:"days"`  // 星期几（0 表示周日，1-6 表示周一至周六），为空表示每天
+	Start string `json:"start"` // 开始时间（HH:MM）
+	End   string `json:"end"`   // 结束时间（HH:MM），早于开始时间表示跨越午夜（如 22:00-07:00）
+}
+
+// QuietHoursConfig 表示勿扰时段配置
+type QuietHoursConfig struct {
+	Enabled  bool          `json:"enabled"`  // 是否启用
+	Timezone string        `json:"timezone"` // 时区（IANA 名称，如 Asia/Shanghai），为空使用系统时区
+	Windows  []QuietWindow `json:"windows"`  // 勿扰时间段
+}
+
+// DigestConfig 表示汇总提醒配置
+type DigestConfig struct {
+	Enabled   bool `json:"enabled"`   // 是否启用
+	Threshold int  `json:"threshold"` // 同一来源一次拉取到的新通知超过该条数时合并为一条汇总，0 表示不按条数合并
+	Window    int  `json:"window"`    // 合并窗口（秒），窗口内同一来源的新通知合并为一条汇总，0 表示不按窗口合并
+}
+
+// APIConfig 表示本机 HTTP 接口配置
+type APIConfig struct {
+	Enabled bool `json:"enabled"` // 是否启用（默认关闭）
+	Port    int  `json:"port"`    // 监听端口（只监听 127.0.0.1），为 0 时使用默认端口
+}
+
 // Config 表示应用配置
 type Config struct {
 	PollInterval int    `json:"poll_interval"` // 轮询间隔（秒），默认 60
@@ -20,4 +85,35 @@ type Config struct {
 
 	// ld246 认证
 	Ld246 Ld246Config `json:"ld246"`
+
+	// 通知渠道，同一条通知会同时投递到所有启用的渠道
+	Sinks []SinkConfig `json:"sinks"`
+
+	// 数据源配置（数据源名称 -> 配置），未配置的数据源默认启用并使用全局轮询间隔
+	Sources map[string]SourceConfig `json:"sources"`
+
+	// 通知规则，按顺序对每条新通知执行
+	Rules []Rule `json:"rules"`
+
+	// 勿扰时段，期间收到的通知只记录不弹出，结束后汇总提醒
+	QuietHours QuietHoursConfig `json:"quiet_hours"`
+
+	// 汇总提醒，避免一次弹出大量通知
+	Digest DigestConfig `json:"digest"`
+
+	// 本机 HTTP 接口，供编辑器插件、状态栏等工具读取通知
+	API APIConfig `json:"api"`
+}
+
+// SourceEnabled 检查数据源是否启用
+func (c *Config) SourceEnabled(name string) bool {
+	return !c.Sources[name].Disabled
+}
+
+// SourcePollInterval 获取数据源的轮询间隔（秒）
+func (c *Config) SourcePollInterval(name string) int {
+	if interval := c.Sources[name].PollInterval; interval > 0 {
+		return interval
+	}
+	return c.PollInterval
 }
@@ -2,10 +2,94 @@ package types
 
 // Notification 表示一个通知消息
 type Notification struct {
-	ID      string `json:"id"`      // 唯一标识符
-	Title   string `json:"title"`   // 标题
-	Content string `json:"content"` // 内容摘要
-	Link    string `json:"link"`    // 跳转链接
-	Source  string `json:"source"`  // 来源（ld246 或 github）
-	Time    int64  `json:"time"`    // 时间戳
+	ID         string `json:"id"`         // 唯一标识符
+	ThreadKey  string `json:"thread_key"` // 所属会话（GitHub 线程、ld246 帖子），同一会话的多次更新归为一组
+	Title      string `json:"title"`      // 标题
+	Content    string `json:"content"`    // 内容摘要
+	Link       string `json:"link"`       // 跳转链接
+	Source     string `json:"source"`     // 来源（ld246 或 github）
+	Repository string `json:"repository"` // 所属仓库（owner/repo，仅 GitHub）
+	Reason     string `json:"reason"`     // 通知原因（GitHub 的 reason，如 mention、ci_activity）
+	Kind       string `json:"kind"`       // 通知类型（GitHub 的 subject type，如 PullRequest；ld246 的消息分类，如 at）
+	Priority   string `json:"priority"`   // 优先级：low, normal, high，为空表示 normal（由规则设置）
+	Author     string `json:"author"`     // 触发通知的用户（ld246 的作者名称；GitHub 接口不提供，为空）
+	AvatarURL  string `json:"avatar_url"` // 头像（ld246 为作者头像，GitHub 为仓库所有者头像）
+	Time       int64  `json:"time"`       // 时间戳
+	Unread     bool   `json:"unread"`     // 拉取时站点上是否未读（Read 是本地状态）
+	Read       bool   `json:"read"`       // 是否已读
+	Archived   bool   `json:"archived"`   // 是否已归档（归档后不在收件箱中显示）
+	Starred    bool   `json:"starred"`    // 是否已加星标
+
+	SnoozedUntil int64 `json:"snoozed_until"` // 稍后提醒时间（毫秒），0 表示未设置；到期后重新提醒并清零
+
+	Actions []NotificationAction `json:"actions,omitempty"` // 通知上可以执行的动作，由数据源在拉取时附加
+
+	Extra map[string]string `json:"extra,omitempty"` // 来源特有的其他信息，如 GitHub 的 subject_url、ld246 的 data_type
+}
+
+// 通知动作标识
+const (
+	ActionOpen       = "open"        // 在浏览器中打开并标记为已读
+	ActionMarkRead   = "mark-read"   // 标记为已读
+	ActionMarkDone   = "mark-done"   // 标记为完成（归档，并在站点上标记为完成）
+	ActionMuteThread = "mute-thread" // 静音通知所在的会话
+	ActionSnooze     = "snooze"      // 稍后提醒
+	ActionReply      = "reply"       // 在浏览器中打开回复页面
+)
+
+// NotificationAction 通知上可以执行的动作
+// 桌面通知按钮、主界面和本机接口都通过动作标识调用调度器执行
+type NotificationAction struct {
+	ID    string `json:"id"`            // 动作标识，如 open、mark-read
+	Label string `json:"label"`         // 按钮文字
+	URL   string `json:"url,omitempty"` // 动作需要打开的链接（如回复页面），为空时使用通知的链接
+}
+
+// UnixMilli 返回毫秒时间戳
+// 不同来源的 Time 精度不同（GitHub 为秒，ld246 为毫秒），排序和比较前需要统一
+func (n *Notification) UnixMilli() int64 {
+	if n.Time > 0 && n.Time < 1000000000000 {
+		return n.Time * 1000
+	}
+	return n.Time
+}
+
+// Action 查找通知上的动作，没有该动作时返回 nil
+func (n *Notification) Action(id string) *NotificationAction {
+	for i := range n.Actions {
+		if n.Actions[i].ID == id {
+			return &n.Actions[i]
+		}
+	}
+	return nil
+}
+
+// Thread 返回通知所属会话的标识，没有会话信息（如旧版本保存的通知）时为通知自身的 ID
+func (n *Notification) Thread() string {
+	if n.ThreadKey != "" {
+		return n.ThreadKey
+	}
+	return n.ID
+}
+
+// NotificationThread 表示一个会话，即同一 GitHub 线程或 ld246 帖子的多次更新
+type NotificationThread struct {
+	Key    string        `json:"key"`    // 会话标识
+	Latest *Notification `json:"latest"` // 最近一次更新
+	Count  int           `json:"count"`  // 更新次数
+	Unread int           `json:"unread"` // 未读的更新次数
+	Muted  bool          `json:"muted"`  // 会话是否已静音
+}
+
+// NotificationQuery 表示通知历史查询条件
+type NotificationQuery struct {
+	Source   string `json:"source"`   // 来源，为空表示全部
+	Read     *bool  `json:"read"`     // 已读状态，为空表示全部
+	Archived *bool  `json:"archived"` // 归档状态，为空表示全部
+	Starred  *bool  `json:"starred"`  // 星标状态，为空表示全部
+	Snoozed  *bool  `json:"snoozed"`  // 是否设置了稍后提醒，为空表示全部
+	Since    int64  `json:"since"`    // 起始时间（毫秒，包含），0 表示不限
+	Until    int64  `json:"until"`    // 结束时间（毫秒，不包含），0 表示不限
+	Limit    int    `json:"limit"`    // 最多返回条数，0 表示不限
+	Offset   int    `json:"offset"`   // 跳过条数
 }